@@ -3,6 +3,7 @@ package digikey
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,26 +14,45 @@ import (
 )
 
 const (
-	defaultTokenURL   = "https://api.digikey.com/v1/oauth2/token"
-	tokenExpiryBuffer = 60 * time.Second
+	defaultTokenURL     = "https://api.digikey.com/v1/oauth2/token"
+	defaultAuthorizeURL = "https://api.digikey.com/v1/oauth2/authorize"
+	tokenExpiryBuffer   = 60 * time.Second
 )
 
 // tokenResponse represents the OAuth2 token response from Digi-Key.
 type tokenResponse struct {
-	AccessToken string `json:"access_token"`
-	TokenType   string `json:"token_type"`
-	ExpiresIn   int    `json:"expires_in"`
+	AccessToken           string `json:"access_token"`
+	TokenType             string `json:"token_type"`
+	ExpiresIn             int    `json:"expires_in"`
+	RefreshToken          string `json:"refresh_token"`
+	RefreshTokenExpiresIn int    `json:"refresh_token_expires_in"`
 }
 
-// tokenManager handles OAuth2 token caching and refresh.
+// tokenManager handles OAuth2 token caching and refresh. It uses the
+// client credentials grant unless the authorization code flow is enabled,
+// in which case tokens come from exchangeCode or restore and are renewed
+// with the refresh token.
 type tokenManager struct {
-	mu           sync.RWMutex
-	httpClient   *http.Client
-	clientID     string
-	clientSecret string
-	tokenURL     string
-	accessToken  string
-	tokenExpiry  time.Time
+	mu            sync.RWMutex // Guards the fields below httpClient
+	refreshMu     sync.Mutex   // Serializes token requests
+	httpClient    *http.Client
+	clientID      string
+	clientSecret  string
+	tokenURL      string
+	authorizeURL  string
+	authCode      bool   // Tokens come from the authorization code flow
+	redirectURL   string // Redirect URL registered for the authorization code flow
+	accessToken   string
+	tokenExpiry   time.Time
+	refreshTok    string
+	refreshExpiry time.Time
+	store         TokenStore  // Optional; shares tokens with other clients
+	retry         RetryConfig // Retries for token requests
+
+	lastRefresh   time.Time // Last successful refresh
+	lastError     error     // Error from the last refresh attempt
+	lastErrorTime time.Time
+	failures      int // Consecutive failed refresh attempts
 }
 
 func newTokenManager(httpClient *http.Client, clientID, clientSecret, tokenURL string) *tokenManager {
@@ -44,39 +64,175 @@ func newTokenManager(httpClient *http.Client, clientID, clientSecret, tokenURL s
 		clientID:     clientID,
 		clientSecret: clientSecret,
 		tokenURL:     tokenURL,
+		authorizeURL: defaultAuthorizeURL,
 	}
 }
 
 // getToken returns a valid access token, refreshing if necessary.
 func (tm *tokenManager) getToken(ctx context.Context) (string, error) {
+	if token, ok := tm.validToken(tokenExpiryBuffer); ok {
+		return token, nil
+	}
+	return tm.refreshToken(ctx)
+}
+
+// validToken returns the access token if it stays valid for at least margin.
+func (tm *tokenManager) validToken(margin time.Duration) (string, bool) {
 	tm.mu.RLock()
-	token := tm.accessToken
-	expiry := tm.tokenExpiry
-	tm.mu.RUnlock()
+	defer tm.mu.RUnlock()
+
+	if tm.accessToken != "" && time.Now().Before(tm.tokenExpiry.Add(-margin)) {
+		return tm.accessToken, true
+	}
+	return "", false
+}
+
+// refreshToken obtains a new access token from the OAuth2 endpoint, using
+// the refresh token if there is one and client credentials otherwise.
+func (tm *tokenManager) refreshToken(ctx context.Context) (string, error) {
+	return tm.renew(ctx, tokenExpiryBuffer)
+}
+
+// renew obtains a new access token unless the current one stays valid for
+// at least margin. Renewals are serialized by refreshMu, while tm.mu is only
+// held briefly, so getToken keeps returning a still-valid token meanwhile.
+func (tm *tokenManager) renew(ctx context.Context, margin time.Duration) (string, error) {
+	tm.refreshMu.Lock()
+	defer tm.refreshMu.Unlock()
 
-	if token != "" && time.Now().Before(expiry.Add(-tokenExpiryBuffer)) {
+	if token, ok := tm.validToken(margin); ok {
 		return token, nil
 	}
 
-	return tm.refreshToken(ctx)
+	token, err := tm.fetchToken(ctx, margin)
+	tm.recordRefresh(err)
+	return token, err
 }
 
-// refreshToken obtains a new access token from the OAuth2 endpoint.
-func (tm *tokenManager) refreshToken(ctx context.Context) (string, error) {
-	tm.mu.Lock()
-	defer tm.mu.Unlock()
+// fetchToken obtains a new access token from the store or the OAuth2
+// endpoint. The caller must hold tm.refreshMu.
+func (tm *tokenManager) fetchToken(ctx context.Context, margin time.Duration) (string, error) {
+	if tm.store != nil {
+		// Another client sharing the store may have refreshed already
+		if locker, ok := tm.store.(TokenLocker); ok {
+			unlock, err := locker.LockToken(ctx, tm.storeKey())
+			if err != nil {
+				return "", fmt.Errorf("digikey: token store: %w", err)
+			}
+			defer unlock()
+		}
+		stored, err := tm.store.Load(ctx, tm.storeKey())
+		if err != nil {
+			return "", fmt.Errorf("digikey: token store: %w", err)
+		}
+		if stored != (Token{}) {
+			tm.mu.Lock()
+			tm.restoreLocked(stored)
+			tm.mu.Unlock()
+		}
+		if token, ok := tm.validToken(margin); ok {
+			return token, nil
+		}
+	}
+
+	data, err := tm.renewalGrant()
+	if err != nil {
+		return "", err
+	}
+	tokenResp, err := tm.requestToken(ctx, data)
+	if err != nil {
+		return "", err
+	}
+
+	token := tm.setToken(tokenResp)
+	if err := tm.save(ctx, token); err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// renewalGrant returns the token request form for renewing the access
+// token: the refresh token grant if there is a usable refresh token, and
+// client credentials otherwise.
+func (tm *tokenManager) renewalGrant() (url.Values, error) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	switch {
+	case tm.refreshTok != "" && (tm.refreshExpiry.IsZero() || time.Now().Before(tm.refreshExpiry)):
+		return url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {tm.refreshTok},
+		}, nil
+	case tm.authCode:
+		return nil, ErrAuthorizationRequired
+	default:
+		return url.Values{
+			"grant_type": {"client_credentials"},
+		}, nil
+	}
+}
+
+// exchangeCode obtains tokens for an authorization code returned to the
+// redirect URL.
+func (tm *tokenManager) exchangeCode(ctx context.Context, code string) (Token, error) {
+	tm.refreshMu.Lock()
+	defer tm.refreshMu.Unlock()
+
+	tokenResp, err := tm.requestToken(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {tm.redirectURL},
+	})
+	if err != nil {
+		return Token{}, err
+	}
+
+	token := tm.setToken(tokenResp)
+	err = tm.save(ctx, token)
+	tm.recordRefresh(err)
+	if err != nil {
+		return Token{}, err
+	}
+	return token, nil
+}
+
+// requestToken posts a token request to the OAuth2 endpoint, retrying rate
+// limiting, server errors and timeouts according to tm.retry.
+func (tm *tokenManager) requestToken(ctx context.Context, data url.Values) (tokenResponse, error) {
+	for attempt := 0; ; attempt++ {
+		tokenResp, retryAfter, err := tm.requestTokenOnce(ctx, data)
+		if err == nil || attempt >= tm.retry.MaxRetries || !retryTokenRequest(err) {
+			return tokenResp, err
+		}
 
-	if tm.accessToken != "" && time.Now().Before(tm.tokenExpiry.Add(-tokenExpiryBuffer)) {
-		return tm.accessToken, nil
+		backoff := tm.retry.calculateBackoff(attempt)
+		if retryAfter > backoff {
+			backoff = min(retryAfter, tm.retry.MaxBackoff)
+		}
+		if err := sleep(ctx, backoff); err != nil {
+			return tokenResp, err
+		}
 	}
+}
 
-	data := url.Values{
-		"grant_type": {"client_credentials"},
+// retryTokenRequest reports whether a failed token request should be retried.
+func retryTokenRequest(err error) bool {
+	var tokenErr *TokenError
+	if errors.As(err, &tokenErr) {
+		return tokenErr.Retryable()
 	}
+	return shouldRetry(errors.Unwrap(err), 0)
+}
+
+// requestTokenOnce performs a single token request. It also returns the
+// Retry-After delay of a rate limited response.
+func (tm *tokenManager) requestTokenOnce(ctx context.Context, data url.Values) (tokenResponse, time.Duration, error) {
+	var tokenResp tokenResponse
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tm.tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
-		return "", fmt.Errorf("digikey: failed to create token request: %w", err)
+		return tokenResp, 0, fmt.Errorf("digikey: failed to create token request: %w", err)
 	}
 
 	// Use HTTP Basic Auth for client credentials (not form data)
@@ -85,7 +241,7 @@ func (tm *tokenManager) refreshToken(ctx context.Context) (string, error) {
 
 	resp, err := tm.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("digikey: token request failed: %w", err)
+		return tokenResp, 0, fmt.Errorf("digikey: token request failed: %w", err)
 	}
 	defer func() {
 		_ = resp.Body.Close()
@@ -93,36 +249,126 @@ func (tm *tokenManager) refreshToken(ctx context.Context) (string, error) {
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("digikey: failed to read token response: %w", err)
+		return tokenResp, 0, fmt.Errorf("digikey: failed to read token response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		tokenErr := &TokenError{
+			StatusCode: resp.StatusCode,
+			RequestID:  resp.Header.Get("X-Request-Id"),
+		}
 		var authErr AuthError
 		if err := json.Unmarshal(body, &authErr); err == nil && authErr.Err != "" {
-			return "", &authErr
-		}
-		return "", &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    "token request failed",
-			Details:    string(body),
+			tokenErr.Code = authErr.Err
+			tokenErr.Description = authErr.Description
+		} else {
+			tokenErr.Details = string(body)
 		}
+		retryAfter := time.Duration(parseRetryAfter(resp.Header.Get("Retry-After"))) * time.Second
+		return tokenResp, retryAfter, tokenErr
 	}
 
-	var tokenResp tokenResponse
 	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		return "", fmt.Errorf("digikey: failed to parse token response: %w", err)
+		return tokenResp, 0, fmt.Errorf("digikey: failed to parse token response: %w", err)
 	}
+	return tokenResp, 0, nil
+}
 
+// setToken stores a token response and returns the resulting tokens. A
+// response without a refresh token keeps the current one.
+func (tm *tokenManager) setToken(tokenResp tokenResponse) Token {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	now := time.Now()
 	tm.accessToken = tokenResp.AccessToken
-	tm.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	tm.tokenExpiry = now.Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	if tokenResp.RefreshToken != "" {
+		tm.refreshTok = tokenResp.RefreshToken
+		tm.refreshExpiry = time.Time{}
+		if tokenResp.RefreshTokenExpiresIn > 0 {
+			tm.refreshExpiry = now.Add(time.Duration(tokenResp.RefreshTokenExpiresIn) * time.Second)
+		}
+	}
+	return tm.tokenLocked()
+}
 
-	return tm.accessToken, nil
+// token returns the current tokens.
+func (tm *tokenManager) token() Token {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.tokenLocked()
 }
 
-// invalidate clears the cached token.
-func (tm *tokenManager) invalidate() {
+// tokenLocked returns the current tokens. The caller must hold tm.mu.
+func (tm *tokenManager) tokenLocked() Token {
+	return Token{
+		AccessToken:   tm.accessToken,
+		RefreshToken:  tm.refreshTok,
+		Expiry:        tm.tokenExpiry,
+		RefreshExpiry: tm.refreshExpiry,
+	}
+}
+
+// restore replaces the current tokens with a saved token and enables the
+// authorization code flow.
+func (tm *tokenManager) restore(token Token) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
+	tm.authCode = true
+	tm.restoreLocked(token)
+}
+
+// restoreLocked replaces the current tokens. The caller must hold tm.mu.
+func (tm *tokenManager) restoreLocked(token Token) {
+	tm.accessToken = token.AccessToken
+	tm.tokenExpiry = token.Expiry
+	tm.refreshTok = token.RefreshToken
+	tm.refreshExpiry = token.RefreshExpiry
+}
+
+// storeKey returns the TokenStore key for this client and grant type.
+func (tm *tokenManager) storeKey() string {
+	if tm.authCode {
+		return tm.clientID + ":authorization_code"
+	}
+	return tm.clientID + ":client_credentials"
+}
+
+// save writes token to the store, if any.
+func (tm *tokenManager) save(ctx context.Context, token Token) error {
+	if tm.store == nil {
+		return nil
+	}
+	if err := tm.store.Save(ctx, tm.storeKey(), token); err != nil {
+		return fmt.Errorf("digikey: token store: %w", err)
+	}
+	return nil
+}
+
+// invalidate clears the cached access token, in the store too unless
+// another client has already replaced it. The refresh token is kept so a
+// new access token can be obtained. Store errors are ignored, as the next
+// refresh replaces the stored token anyway.
+func (tm *tokenManager) invalidate(ctx context.Context) {
+	tm.mu.Lock()
+	token := tm.accessToken
 	tm.accessToken = ""
 	tm.tokenExpiry = time.Time{}
+	tm.mu.Unlock()
+
+	if tm.store == nil || token == "" {
+		return
+	}
+	if locker, ok := tm.store.(TokenLocker); ok {
+		unlock, err := locker.LockToken(ctx, tm.storeKey())
+		if err != nil {
+			return
+		}
+		defer unlock()
+	}
+	if stored, err := tm.store.Load(ctx, tm.storeKey()); err == nil && stored.AccessToken == token {
+		stored.AccessToken, stored.Expiry = "", time.Time{}
+		_ = tm.store.Save(ctx, tm.storeKey(), stored)
+	}
 }
@@ -2,6 +2,7 @@ package digikey
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -81,7 +82,7 @@ func TestTokenManagerInvalidate(t *testing.T) {
 	token1, _ := tm.getToken(ctx)
 
 	// Invalidate
-	tm.invalidate()
+	tm.invalidate(context.Background())
 
 	// Next call should fetch new token
 	tm.tokenExpiry = time.Now().Add(-1 * time.Second) // force refresh
@@ -109,8 +110,16 @@ func TestTokenManagerAuthError(t *testing.T) {
 	if err == nil {
 		t.Error("expected auth error")
 	}
-	if _, ok := err.(*AuthError); !ok {
-		t.Errorf("expected AuthError, got %T", err)
+	tokenErr, ok := err.(*TokenError)
+	if !ok {
+		t.Fatalf("expected TokenError, got %T", err)
+	}
+	if tokenErr.StatusCode != http.StatusUnauthorized || tokenErr.Code != "invalid_client" || tokenErr.Retryable() {
+		t.Errorf("unexpected TokenError %+v", tokenErr)
+	}
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected the error to match AuthError and ErrUnauthorized, got %v", err)
 	}
 }
 
@@ -183,3 +192,74 @@ func TestTokenManagerCaching(t *testing.T) {
 		t.Errorf("expected 1 call, got %d", calls)
 	}
 }
+
+// TestTokenManagerRetry tests retrying server errors from the token endpoint.
+func TestTokenManagerRetry(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	tm := newTokenManager(server.Client(), "id", "secret", server.URL)
+	tm.retry = RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, Multiplier: 2}
+
+	token, err := tm.getToken(context.Background())
+	if err != nil || token != "token" {
+		t.Fatalf("expected token after retries, got %q, %v", token, err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+// TestTokenManagerNoRetryOnRejection tests that rejected credentials are
+// not retried.
+func TestTokenManagerNoRetryOnRejection(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+	}))
+	defer server.Close()
+
+	tm := newTokenManager(server.Client(), "id", "secret", server.URL)
+	tm.retry = RetryConfig{MaxRetries: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, Multiplier: 2}
+
+	if _, err := tm.getToken(context.Background()); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+// TestTokenManagerOutageError tests the error for a non-JSON server failure.
+func TestTokenManagerOutageError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-123")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("<html>Bad Gateway</html>"))
+	}))
+	defer server.Close()
+
+	tm := newTokenManager(server.Client(), "id", "secret", server.URL)
+
+	_, err := tm.getToken(context.Background())
+	var tokenErr *TokenError
+	if !errors.As(err, &tokenErr) {
+		t.Fatalf("expected TokenError, got %v", err)
+	}
+	if tokenErr.StatusCode != http.StatusBadGateway || tokenErr.RequestID != "req-123" || tokenErr.Details == "" || !tokenErr.Retryable() {
+		t.Errorf("unexpected TokenError %+v", tokenErr)
+	}
+	if !errors.Is(err, ErrServerError) || errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrServerError only, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package digikey
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// defaultBatchConcurrency is the number of concurrent requests used when
+// BatchOptions.Concurrency is not set.
+const defaultBatchConcurrency = 4
+
+// BatchOptions configures ProductDetailsBatch.
+type BatchOptions struct {
+	Concurrency int // Maximum concurrent API requests (default 4)
+}
+
+// BatchResult is the outcome for one product number in a batch.
+type BatchResult struct {
+	Response *ProductDetailsResponse
+	Err      error
+	Cached   bool // Served from the cache without an API call
+}
+
+// ProductDetailsBatch retrieves details for many products at once. Cached
+// products are served without network calls; the rest are fetched with
+// bounded concurrency, queueing for rate limit slots as needed. Parts are
+// not queued past the minute window: once the day budget is used up, the
+// remaining parts fail with a *RateLimitError.
+//
+// The returned map has one entry per distinct product number. A failure for
+// one product is recorded in its BatchResult and does not stop the others.
+// opts may be nil.
+func (c *Client) ProductDetailsBatch(ctx context.Context, productNumbers []string, opts *BatchOptions) map[string]BatchResult {
+	concurrency := defaultBatchConcurrency
+	if opts != nil && opts.Concurrency > 0 {
+		concurrency = opts.Concurrency
+	}
+
+	results := make(map[string]BatchResult, len(productNumbers))
+	var pending []string
+	for _, pn := range productNumbers {
+		if _, done := results[pn]; done {
+			continue
+		}
+		if pn == "" {
+			results[pn] = BatchResult{Err: fmt.Errorf("%w: product number is required", ErrInvalidRequest)}
+			continue
+		}
+		if resp, ok := c.cachedDetails(pn); ok {
+			results[pn] = BatchResult{Response: resp, Cached: true}
+			continue
+		}
+		results[pn] = BatchResult{}
+		pending = append(pending, pn)
+	}
+
+	// A fixed pool of workers queues for rate limit slots, so parts are
+	// fetched as fast as the limiter allows rather than failing
+	jobs := make(chan string)
+	queued := withRateLimitQueue(ctx)
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for i := 0; i < min(concurrency, len(pending)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pn := range jobs {
+				var result BatchResult
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+				} else {
+					result.Response, result.Err = c.ProductDetails(queued, pn)
+				}
+
+				mu.Lock()
+				results[pn] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, pn := range pending {
+		jobs <- pn
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// cachedDetails returns the cached product details for productNumber, if
+// any. Only hits are counted; a miss is counted when the product is fetched.
+func (c *Client) cachedDetails(productNumber string) (*ProductDetailsResponse, bool) {
+	key := c.environment.cacheKey(cacheKeyForDetails(c.getLocale(), productNumber))
+
+	var resp ProductDetailsResponse
+	if _, ok := c.peekCache(key, &resp); !ok {
+		return nil, false
+	}
+	c.cacheCounters.record(key, true)
+	return &resp, true
+}
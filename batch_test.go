@@ -0,0 +1,177 @@
+package digikey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestProductDetailsBatch tests fan-out with partial failures and cache hits.
+func TestProductDetailsBatch(t *testing.T) {
+	var mu sync.Mutex
+	requested := make(map[string]int)
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		pn := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/products/v4/search/"), "/productdetails")
+
+		mu.Lock()
+		requested[pn]++
+		mu.Unlock()
+
+		if pn == "MISSING" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ProductDetailsResponse{Product: Product{DigiKeyProductNumber: pn}})
+	})
+
+	// Pre-populate the cache for one product
+	cached, _ := json.Marshal(ProductDetailsResponse{Product: Product{DigiKeyProductNumber: "CACHED"}})
+	client.cache.Set(cacheKeyForDetails(client.getLocale(), "CACHED"), cached, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	parts := []string{"A-ND", "B-ND", "A-ND", "MISSING", "CACHED", ""}
+	results := client.ProductDetailsBatch(ctx, parts, &BatchOptions{Concurrency: 2})
+
+	if len(results) != 5 {
+		t.Fatalf("expected 5 distinct results, got %d", len(results))
+	}
+	for _, pn := range []string{"A-ND", "B-ND"} {
+		r := results[pn]
+		if r.Err != nil {
+			t.Errorf("%s: unexpected error: %v", pn, r.Err)
+			continue
+		}
+		if r.Response.Product.DigiKeyProductNumber != pn || r.Cached {
+			t.Errorf("%s: unexpected result %+v", pn, r)
+		}
+	}
+	if !errors.Is(results["MISSING"].Err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for MISSING, got %v", results["MISSING"].Err)
+	}
+	if !results["CACHED"].Cached || results["CACHED"].Response == nil {
+		t.Errorf("expected CACHED to be served from cache, got %+v", results["CACHED"])
+	}
+	if !errors.Is(results[""].Err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for empty part, got %v", results[""].Err)
+	}
+
+	if requested["A-ND"] != 1 {
+		t.Errorf("expected duplicate part to be requested once, got %d", requested["A-ND"])
+	}
+	if requested["CACHED"] != 0 {
+		t.Error("cached part should not be requested")
+	}
+
+	// Each distinct part is looked up in the cache once
+	if s := client.CacheStats().Kinds["details"]; s.Hits != 1 || s.Misses != 3 {
+		t.Errorf("expected 1 hit and 3 misses, got %+v", s)
+	}
+}
+
+// TestProductDetailsBatchConcurrency tests that concurrency is bounded.
+func TestProductDetailsBatchConcurrency(t *testing.T) {
+	var inFlight, peak int32
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		_, _ = w.Write([]byte(`{"Product":{}}`))
+	}, WithoutCache())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	parts := []string{"1", "2", "3", "4", "5", "6", "7", "8"}
+	results := client.ProductDetailsBatch(ctx, parts, &BatchOptions{Concurrency: 3})
+
+	for pn, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: unexpected error: %v", pn, r.Err)
+		}
+	}
+	if peak > 3 {
+		t.Errorf("expected at most 3 concurrent requests, got %d", peak)
+	}
+}
+
+// TestProductDetailsBatchRateLimit tests that workers queue for rate limit
+// slots instead of failing when the window is used up.
+func TestProductDetailsBatchRateLimit(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Product":{}}`))
+	}, WithoutCache())
+	limiter := NewRateLimiterWithLimits(2, 100)
+	limiter.minuteResetTime = time.Now().Add(50 * time.Millisecond)
+	client.rateLimiter = limiter
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	results := client.ProductDetailsBatch(ctx, []string{"1", "2", "3", "4"}, &BatchOptions{Concurrency: 4})
+	for pn, r := range results {
+		if r.Err != nil {
+			t.Errorf("%s: unexpected error: %v", pn, r.Err)
+		}
+	}
+}
+
+// TestProductDetailsBatchDayLimit tests that parts fail with the day limit
+// instead of queueing until it resets.
+func TestProductDetailsBatchDayLimit(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Product":{}}`))
+	}, WithoutCache(), WithRateLimiter(NewRateLimiterWithLimits(100, 1)))
+
+	start := time.Now()
+	results := client.ProductDetailsBatch(context.Background(), []string{"1", "2", "3"}, nil)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("batch waited %v for the day limit", elapsed)
+	}
+
+	var failed int
+	for pn, r := range results {
+		var rateErr *RateLimitError
+		switch {
+		case r.Err == nil:
+		case errors.As(r.Err, &rateErr) && rateErr.Type == "day":
+			failed++
+		default:
+			t.Errorf("%s: expected a day RateLimitError, got %v", pn, r.Err)
+		}
+	}
+	if failed != 2 {
+		t.Errorf("expected 2 parts over the day limit, got %d", failed)
+	}
+}
+
+// TestProductDetailsBatchCanceled tests that a canceled context fails pending parts.
+func TestProductDetailsBatchCanceled(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Product":{}}`))
+	}, WithoutCache())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := client.ProductDetailsBatch(ctx, []string{"1", "2"}, nil)
+	for pn, r := range results {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", pn, r.Err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package digikey
 
 import (
+	"container/list"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -15,23 +16,78 @@ type Cache interface {
 	Delete(key string)
 }
 
-// MemoryCache is a simple in-memory cache with TTL support.
+// ClearableCache is implemented by caches that can remove all entries at
+// once. Client.ClearCache uses it when available.
+type ClearableCache interface {
+	Clear()
+}
+
+// InvalidatingCache is implemented by caches that can remove entries
+// selectively. Client.InvalidateCache requires it.
+type InvalidatingCache interface {
+	// DeleteMatching removes the entries whose keys match and returns how
+	// many were removed.
+	DeleteMatching(match func(key string) bool) int
+}
+
+// MemoryCache is an in-memory cache with TTL support. It can optionally be
+// bounded by entry count and total size, evicting the least recently used
+// entries to stay within its limits.
 type MemoryCache struct {
-	mu      sync.RWMutex
-	entries map[string]*cacheEntry
-	ttl     time.Duration
+	mu         sync.Mutex
+	entries    map[string]*list.Element // Values are *cacheEntry
+	lru        *list.List               // Most recently used at the front
+	ttl        time.Duration
+	maxEntries int
+	maxBytes   int64
+	bytes      int64
+	evictions  int64
+	expired    int64
+	kinds      map[string]*CacheKindStats
+
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 type cacheEntry struct {
+	key       string
 	value     []byte
 	expiresAt time.Time
 }
 
+// size returns the bytes counted against MemoryCache's size limit.
+func (e *cacheEntry) size() int64 {
+	return int64(len(e.key) + len(e.value))
+}
+
+// MemoryCacheStats contains MemoryCache usage information.
+type MemoryCacheStats struct {
+	Entries    int
+	Bytes      int64 // Size of keys and values
+	MaxEntries int   // 0 if unlimited
+	MaxBytes   int64 // 0 if unlimited
+	Evictions  int64 // Entries removed to stay within the limits
+	Expired    int64 // Entries removed after their TTL
+}
+
 // NewMemoryCache creates a new in-memory cache with the specified default TTL.
+// Call Close when the cache is no longer needed to stop its cleanup goroutine.
 func NewMemoryCache(defaultTTL time.Duration) *MemoryCache {
+	return NewMemoryCacheWithLimits(defaultTTL, 0, 0)
+}
+
+// NewMemoryCacheWithLimits creates an in-memory cache holding at most
+// maxEntries entries and maxBytes bytes of keys and values. A limit of 0
+// means unlimited.
+func NewMemoryCacheWithLimits(defaultTTL time.Duration, maxEntries int, maxBytes int64) *MemoryCache {
 	c := &MemoryCache{
-		entries: make(map[string]*cacheEntry),
-		ttl:     defaultTTL,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		ttl:        defaultTTL,
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		kinds:      make(map[string]*CacheKindStats),
+		done:       make(chan struct{}),
 	}
 	go c.cleanupLoop()
 	return c
@@ -39,23 +95,27 @@ func NewMemoryCache(defaultTTL time.Duration) *MemoryCache {
 
 // Get retrieves a value from the cache.
 func (c *MemoryCache) Get(key string) ([]byte, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	entry, ok := c.entries[key]
+	elem, ok := c.entries[key]
 	if !ok {
 		return nil, false
 	}
 
+	entry := elem.Value.(*cacheEntry)
 	if time.Now().After(entry.expiresAt) {
+		c.expire(elem)
 		return nil, false
 	}
 
+	c.lru.MoveToFront(elem)
 	return entry.value, true
 }
 
 // Set stores a value in the cache with the specified TTL.
-// If ttl is 0, the default TTL is used.
+// If ttl is 0, the default TTL is used. Values larger than the cache's
+// size limit are not stored.
 func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
 	if ttl == 0 {
 		ttl = c.ttl
@@ -64,26 +124,99 @@ func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.entries[key] = &cacheEntry{
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+
+	entry := &cacheEntry{
+		key:       key,
 		value:     value,
 		expiresAt: time.Now().Add(ttl),
 	}
+	if c.maxBytes > 0 && entry.size() > c.maxBytes {
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(entry)
+	c.bytes += entry.size()
+	k := c.kind(key)
+	k.Entries++
+	k.Bytes += entry.size()
+
+	for (c.maxEntries > 0 && len(c.entries) > c.maxEntries) || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		evicted := c.removeElement(c.lru.Back())
+		c.evictions++
+		c.kind(evicted.key).Evictions++
+	}
 }
 
 // Delete removes a value from the cache.
 func (c *MemoryCache) Delete(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	delete(c.entries, key)
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// DeleteMatching removes the entries whose keys match and returns how many
+// were removed.
+func (c *MemoryCache) DeleteMatching(match func(key string) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, elem := range c.entries {
+		if match(key) {
+			c.removeElement(elem)
+			removed++
+		}
+	}
+	return removed
+}
+
+// removeElement removes an entry and returns it. The caller must hold c.mu.
+func (c *MemoryCache) removeElement(elem *list.Element) *cacheEntry {
+	entry := c.lru.Remove(elem).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.bytes -= entry.size()
+	k := c.kind(entry.key)
+	k.Entries--
+	k.Bytes -= entry.size()
+	return entry
+}
+
+// expire removes an entry whose TTL has passed. The caller must hold c.mu.
+func (c *MemoryCache) expire(elem *list.Element) {
+	entry := c.removeElement(elem)
+	c.expired++
+	c.kind(entry.key).Expired++
+}
+
+// kind returns the counters for key's kind. The caller must hold c.mu.
+func (c *MemoryCache) kind(key string) *CacheKindStats {
+	kind := cacheKind(key)
+	s, ok := c.kinds[kind]
+	if !ok {
+		s = &CacheKindStats{}
+		c.kinds[kind] = s
+	}
+	return s
 }
 
-// cleanupLoop periodically removes expired entries.
+// cleanupLoop periodically removes expired entries until Close is called.
 func (c *MemoryCache) cleanupLoop() {
 	ticker := time.NewTicker(time.Minute)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		c.cleanup()
+	for {
+		select {
+		case <-ticker.C:
+			c.cleanup()
+		case <-c.done:
+			return
+		}
 	}
 }
 
@@ -92,25 +225,65 @@ func (c *MemoryCache) cleanup() {
 	defer c.mu.Unlock()
 
 	now := time.Now()
-	for key, entry := range c.entries {
-		if now.After(entry.expiresAt) {
-			delete(c.entries, key)
+	for _, elem := range c.entries {
+		if now.After(elem.Value.(*cacheEntry).expiresAt) {
+			c.expire(elem)
 		}
 	}
 }
 
+// Close stops the background cleanup goroutine. The cache remains usable,
+// but expired entries are then only removed when read.
+func (c *MemoryCache) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return nil
+}
+
 // Size returns the number of entries in the cache.
 func (c *MemoryCache) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return len(c.entries)
 }
 
+// Stats returns current cache usage.
+func (c *MemoryCache) Stats() MemoryCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return MemoryCacheStats{
+		Entries:    len(c.entries),
+		Bytes:      c.bytes,
+		MaxEntries: c.maxEntries,
+		MaxBytes:   c.maxBytes,
+		Evictions:  c.evictions,
+		Expired:    c.expired,
+	}
+}
+
+// CacheStats returns eviction and expiry counts and current contents by kind
+// of cache key. Hits and misses are counted by the Client.
+func (c *MemoryCache) CacheStats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kinds := make(map[string]CacheKindStats, len(c.kinds))
+	for kind, s := range c.kinds {
+		kinds[kind] = *s
+	}
+	return newCacheStats(kinds)
+}
+
 // Clear removes all entries from the cache.
 func (c *MemoryCache) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.entries = make(map[string]*cacheEntry)
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+	for _, s := range c.kinds {
+		s.Entries, s.Bytes = 0, 0
+	}
 }
 
 // CacheConfig configures caching behavior.
@@ -118,6 +291,16 @@ type CacheConfig struct {
 	Enabled    bool
 	SearchTTL  time.Duration // TTL for search results
 	DetailsTTL time.Duration // TTL for product details
+	MaxEntries int           // Entry limit for the default MemoryCache (0 = unlimited)
+	MaxBytes   int64         // Size limit for the default MemoryCache (0 = unlimited)
+
+	// StaleIfError serves responses up to this long past their TTL when the
+	// API fails or the rate limit is exhausted. 0 disables it.
+	StaleIfError time.Duration
+
+	// StaleWhileRevalidate serves responses up to this long past their TTL
+	// immediately while refreshing them in the background. 0 disables it.
+	StaleWhileRevalidate time.Duration
 }
 
 // DefaultCacheConfig returns the default cache configuration.
@@ -129,7 +312,9 @@ func DefaultCacheConfig() CacheConfig {
 	}
 }
 
-// cacheKeyForSearch generates a cache key for a search request.
+// cacheKeyForSearch generates a cache key for a search request. The key
+// covers every request field, so differently filtered or sorted queries
+// are cached separately.
 func cacheKeyForSearch(locale Locale, req *SearchRequest) string {
 	data, _ := json.Marshal(req)
 	hash := sha256.Sum256(data)
@@ -140,3 +325,9 @@ func cacheKeyForSearch(locale Locale, req *SearchRequest) string {
 func cacheKeyForDetails(locale Locale, productNumber string) string {
 	return "details:" + locale.Site + ":" + locale.Currency + ":" + productNumber
 }
+
+// cacheKeyForPath generates a cache key for a GET request identified by its
+// path and query string.
+func cacheKeyForPath(kind string, locale Locale, path string) string {
+	return kind + ":" + locale.Site + ":" + locale.Currency + ":" + path
+}
@@ -1,6 +1,7 @@
 package digikey
 
 import (
+	"strings"
 	"testing"
 	"time"
 )
@@ -240,3 +241,125 @@ func TestMemoryCacheEmptyValue(t *testing.T) {
 func TestCacheInterface(t *testing.T) {
 	var _ Cache = (*MemoryCache)(nil)
 }
+
+// TestCacheKeyForSearchSort tests that sort options are part of the search cache key.
+func TestCacheKeyForSearchSort(t *testing.T) {
+	locale := DefaultLocale()
+	unsorted := &SearchRequest{Keywords: "LED", Limit: 10}
+	byPrice := &SearchRequest{Keywords: "LED", Limit: 10, SortOptions: &SortOptions{Field: SortByPrice, Direction: SortAscending}}
+	byPriceDesc := &SearchRequest{Keywords: "LED", Limit: 10, SortOptions: &SortOptions{Field: SortByPrice, Direction: SortDescending}}
+	byPriceAgain := &SearchRequest{Keywords: "LED", Limit: 10, SortOptions: &SortOptions{Field: SortByPrice, Direction: SortAscending}}
+
+	if cacheKeyForSearch(locale, unsorted) == cacheKeyForSearch(locale, byPrice) {
+		t.Error("sorted and unsorted searches should have different keys")
+	}
+	if cacheKeyForSearch(locale, byPrice) == cacheKeyForSearch(locale, byPriceDesc) {
+		t.Error("different sort directions should have different keys")
+	}
+	if cacheKeyForSearch(locale, byPrice) != cacheKeyForSearch(locale, byPriceAgain) {
+		t.Error("identical sort options should have the same key")
+	}
+}
+
+// TestMemoryCacheMaxEntries tests LRU eviction by entry count.
+func TestMemoryCacheMaxEntries(t *testing.T) {
+	cache := NewMemoryCacheWithLimits(5*time.Minute, 2, 0)
+	defer func() { _ = cache.Close() }()
+
+	cache.Set("a", []byte("1"), 0)
+	cache.Set("b", []byte("2"), 0)
+	cache.Get("a") // "b" is now least recently used
+	cache.Set("c", []byte("3"), 0)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("expected recently read entry to be kept")
+	}
+	if _, ok := cache.Get("c"); !ok {
+		t.Error("expected new entry to be kept")
+	}
+
+	stats := cache.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 || stats.MaxEntries != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// TestMemoryCacheMaxBytes tests LRU eviction by size.
+func TestMemoryCacheMaxBytes(t *testing.T) {
+	cache := NewMemoryCacheWithLimits(5*time.Minute, 0, 30)
+	defer func() { _ = cache.Close() }()
+
+	value := []byte(strings.Repeat("x", 9)) // 10 bytes with a 1-byte key
+	cache.Set("a", value, 0)
+	cache.Set("b", value, 0)
+	cache.Set("c", value, 0)
+	cache.Set("d", value, 0)
+
+	if _, ok := cache.Get("a"); ok {
+		t.Error("expected oldest entry to be evicted")
+	}
+	if stats := cache.Stats(); stats.Bytes != 30 || stats.Entries != 3 || stats.Evictions != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// Replacing an entry does not count it twice
+	cache.Set("d", []byte("y"), 0)
+	if stats := cache.Stats(); stats.Bytes != 22 || stats.Evictions != 1 {
+		t.Errorf("unexpected stats after replace %+v", stats)
+	}
+
+	// Values larger than the cache are not stored
+	cache.Set("huge", []byte(strings.Repeat("z", 100)), 0)
+	if _, ok := cache.Get("huge"); ok {
+		t.Error("expected oversized value not to be cached")
+	}
+	if stats := cache.Stats(); stats.Entries != 3 {
+		t.Errorf("oversized value should not evict other entries, got %+v", stats)
+	}
+}
+
+// TestMemoryCacheExpiredStats tests counting expired entries.
+func TestMemoryCacheExpiredStats(t *testing.T) {
+	cache := NewMemoryCache(5 * time.Minute)
+	defer func() { _ = cache.Close() }()
+
+	cache.Set("a", []byte("1"), 10*time.Millisecond)
+	cache.Set("b", []byte("2"), 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	cache.Get("a")
+	cache.cleanup()
+
+	if stats := cache.Stats(); stats.Entries != 0 || stats.Expired != 2 || stats.Bytes != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// TestMemoryCacheClose tests that Close stops the cleanup goroutine.
+func TestMemoryCacheClose(t *testing.T) {
+	cache := NewMemoryCache(time.Minute)
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	_ = cache.Close() // Closing twice is safe
+
+	exited := make(chan struct{})
+	go func() {
+		cache.cleanupLoop()
+		close(exited)
+	}()
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("cleanup loop did not exit after Close")
+	}
+
+	// The cache still works after Close
+	cache.Set("key", []byte("value"), 0)
+	if _, ok := cache.Get("key"); !ok {
+		t.Error("expected closed cache to remain usable")
+	}
+}
@@ -0,0 +1,73 @@
+package digikey
+
+import (
+	"net/url"
+	"strings"
+)
+
+// CacheSelector selects cached responses for Client.InvalidateCache. Empty
+// fields match everything, so the zero CacheSelector selects all entries.
+type CacheSelector struct {
+	Kind     string // Response kind, such as "search", "details" or "pricing"
+	Site     string // Locale site the response was cached for
+	Currency string // Locale currency the response was cached for
+
+	// ProductNumber selects responses about one product: its details and
+	// product-specific requests such as pricing, media or substitutions.
+	// Search responses never match.
+	ProductNumber string
+}
+
+// matches reports whether key is selected.
+func (s CacheSelector) matches(key string) bool {
+	parts := strings.SplitN(key, ":", 4)
+	if len(parts) != 4 {
+		return s == CacheSelector{}
+	}
+	kind, site, currency, rest := parts[0], parts[1], parts[2], parts[3]
+
+	if (s.Kind != "" && s.Kind != kind) || (s.Site != "" && s.Site != site) || (s.Currency != "" && s.Currency != currency) {
+		return false
+	}
+	if s.ProductNumber == "" {
+		return true
+	}
+	if kind == "details" {
+		return rest == s.ProductNumber
+	}
+	return productInPath(rest, s.ProductNumber)
+}
+
+// productInPath reports whether path is a product-specific request for
+// productNumber, such as ".../{productNumber}/pricing" or
+// ".../packagetypebyquantity/{productNumber}".
+func productInPath(path, productNumber string) bool {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(strings.TrimPrefix(path, searchBasePath+"/"), "/")
+	escaped := url.PathEscape(productNumber)
+	if segments[0] == "packagetypebyquantity" {
+		return len(segments) == 2 && segments[1] == escaped
+	}
+	return len(segments) > 1 && segments[0] == escaped
+}
+
+// InvalidateCache removes the cached responses selected by sel and returns
+// how many were removed. Only responses from the client's environment are
+// selected. The cache must implement InvalidatingCache, as
+// MemoryCache and DiskCache do; otherwise ErrCacheUnsupported is returned.
+//
+//	// Drop one part's details, pricing and media in every locale
+//	client.InvalidateCache(digikey.CacheSelector{ProductNumber: "497-15360-ND"})
+//
+//	// Drop all German search results
+//	client.InvalidateCache(digikey.CacheSelector{Kind: "search", Site: "DE"})
+func (c *Client) InvalidateCache(sel CacheSelector) (int, error) {
+	cache, ok := c.cache.(InvalidatingCache)
+	if !ok {
+		return 0, ErrCacheUnsupported
+	}
+	return cache.DeleteMatching(func(key string) bool {
+		env, key := splitCacheKey(key)
+		return env == c.environment && sel.matches(key)
+	}), nil
+}
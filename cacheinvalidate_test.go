@@ -0,0 +1,141 @@
+package digikey
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCacheSelectorMatches tests selecting cache keys by kind, locale and product.
+func TestCacheSelectorMatches(t *testing.T) {
+	us := DefaultLocale()
+	de := Locale{Site: "DE", Language: "de", Currency: "EUR"}
+
+	detailsUS := cacheKeyForDetails(us, "P5555-ND")
+	detailsDE := cacheKeyForDetails(de, "P5555-ND")
+	otherDetails := cacheKeyForDetails(us, "P5555-ND-X")
+	searchUS := cacheKeyForSearch(us, &SearchRequest{Keywords: "P5555-ND"})
+	searchDE := cacheKeyForSearch(de, &SearchRequest{Keywords: "P5555-ND"})
+	pricing := cacheKeyForPath("pricing", us, searchBasePath+"/P5555-ND/pricing?limit=5")
+	packageType := cacheKeyForPath("packagetype", us, searchBasePath+"/packagetypebyquantity/P5555-ND?requestedQuantity=10")
+	category := cacheKeyForPath("categories", us, searchBasePath+"/categories/5555")
+
+	tests := []struct {
+		name string
+		sel  CacheSelector
+		key  string
+		want bool
+	}{
+		{"all", CacheSelector{}, searchUS, true},
+		{"kind", CacheSelector{Kind: "search"}, searchDE, true},
+		{"other kind", CacheSelector{Kind: "search"}, detailsUS, false},
+		{"site", CacheSelector{Kind: "search", Site: "US"}, searchUS, true},
+		{"other site", CacheSelector{Kind: "search", Site: "US"}, searchDE, false},
+		{"currency", CacheSelector{Currency: "EUR"}, detailsDE, true},
+		{"product details", CacheSelector{ProductNumber: "P5555-ND"}, detailsUS, true},
+		{"product details other locale", CacheSelector{ProductNumber: "P5555-ND"}, detailsDE, true},
+		{"other product", CacheSelector{ProductNumber: "P5555-ND"}, otherDetails, false},
+		{"product pricing", CacheSelector{ProductNumber: "P5555-ND"}, pricing, true},
+		{"product package type", CacheSelector{ProductNumber: "P5555-ND"}, packageType, true},
+		{"product search", CacheSelector{ProductNumber: "P5555-ND"}, searchUS, false},
+		{"product category", CacheSelector{ProductNumber: "5555"}, category, false},
+		{"malformed key", CacheSelector{Kind: "details"}, "details", false},
+		{"malformed key all", CacheSelector{}, "details", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sel.matches(tt.key); got != tt.want {
+				t.Errorf("%+v.matches(%q) = %v, want %v", tt.sel, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestMemoryCacheDeleteMatching tests selective removal from a MemoryCache.
+func TestMemoryCacheDeleteMatching(t *testing.T) {
+	cache := NewMemoryCache(time.Minute)
+	defer func() { _ = cache.Close() }()
+
+	cache.Set("details:US:USD:a", []byte("1"), 0)
+	cache.Set("details:DE:EUR:a", []byte("2"), 0)
+	cache.Set("search:US:USD:b", []byte("3"), 0)
+
+	removed := cache.DeleteMatching(CacheSelector{Kind: "details"}.matches)
+	if removed != 2 {
+		t.Errorf("expected 2 entries removed, got %d", removed)
+	}
+	if _, ok := cache.Get("search:US:USD:b"); !ok {
+		t.Error("search entry should remain")
+	}
+	if s := cache.CacheStats().Kinds["details"]; s.Entries != 0 || s.Bytes != 0 {
+		t.Errorf("unexpected details stats %+v", s)
+	}
+}
+
+// TestDiskCacheDeleteMatching tests selective removal from a DiskCache.
+func TestDiskCacheDeleteMatching(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Set("details:US:USD:a", []byte("1"), 0)
+	cache.Set("search:US:USD:b", []byte("2"), 0)
+
+	if removed := cache.DeleteMatching(CacheSelector{ProductNumber: "a"}.matches); removed != 1 {
+		t.Errorf("expected 1 entry removed, got %d", removed)
+	}
+	if _, ok := cache.Get("details:US:USD:a"); ok {
+		t.Error("details entry should be removed")
+	}
+	if _, ok := cache.Get("search:US:USD:b"); !ok {
+		t.Error("search entry should remain")
+	}
+}
+
+// mapCache is a minimal Cache without invalidation support.
+type mapCache map[string][]byte
+
+func (m mapCache) Get(key string) ([]byte, bool)                 { v, ok := m[key]; return v, ok }
+func (m mapCache) Set(key string, value []byte, _ time.Duration) { m[key] = value }
+func (m mapCache) Delete(key string)                             { delete(m, key) }
+
+// TestClientInvalidateCache tests invalidation through the client.
+func TestClientInvalidateCache(t *testing.T) {
+	client := NewClient("id", "secret")
+	defer func() { _ = client.Close() }()
+
+	client.cache.Set(cacheKeyForDetails(DefaultLocale(), "P5555-ND"), []byte("{}"), 0)
+	client.cache.Set(cacheKeyForDetails(Locale{Site: "DE", Currency: "EUR"}, "P5555-ND"), []byte("{}"), 0)
+	client.cache.Set(cacheKeyForDetails(DefaultLocale(), "OTHER"), []byte("{}"), 0)
+
+	removed, err := client.InvalidateCache(CacheSelector{ProductNumber: "P5555-ND"})
+	if err != nil {
+		t.Fatalf("InvalidateCache: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("expected 2 entries removed, got %d", removed)
+	}
+
+	custom := NewClient("id", "secret", WithCache(mapCache{}))
+	if _, err := custom.InvalidateCache(CacheSelector{}); !errors.Is(err, ErrCacheUnsupported) {
+		t.Errorf("expected ErrCacheUnsupported, got %v", err)
+	}
+}
+
+// TestClientClearCacheDiskCache tests that ClearCache works with custom caches.
+func TestClientClearCacheDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := NewClient("id", "secret", WithCache(cache))
+
+	cache.Set("details:US:USD:a", []byte("1"), 0)
+	client.ClearCache()
+
+	if size := cache.Size(); size != 0 {
+		t.Errorf("expected empty cache, got %d entries", size)
+	}
+}
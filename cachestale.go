@@ -0,0 +1,118 @@
+package digikey
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CacheMeta describes how a response was served from the cache. It is
+// embedded in the response types of cacheable requests.
+type CacheMeta struct {
+	Stale      bool      // Served after its TTL, see CacheConfig.StaleIfError and StaleWhileRevalidate
+	FreshUntil time.Time // When the stale response's TTL ended
+	StaleErr   error     // The error that caused a stale response; nil if it is being revalidated
+}
+
+// setCacheMeta replaces m. It is promoted to the responses embedding CacheMeta.
+func (m *CacheMeta) setCacheMeta(meta CacheMeta) {
+	*m = meta
+}
+
+// staleEnvelopePrefix starts cache values that record when they go stale.
+// Values without it are plain responses, fresh until the cache drops them.
+var staleEnvelopePrefix = []byte(`{"fresh_until":`)
+
+// staleEnvelope wraps a cached response kept past its TTL for stale serving.
+type staleEnvelope struct {
+	FreshUntil time.Time       `json:"fresh_until"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// staleEntry is a cached response past its TTL.
+type staleEntry struct {
+	value      []byte
+	freshUntil time.Time
+}
+
+// within reports whether e went stale no more than window ago. It is false
+// for a nil entry.
+func (e *staleEntry) within(window time.Duration) bool {
+	return e != nil && window > 0 && time.Since(e.freshUntil) <= window
+}
+
+// staleWindow returns how long entries are kept past their TTL.
+func (c CacheConfig) staleWindow() time.Duration {
+	return max(c.StaleIfError, c.StaleWhileRevalidate)
+}
+
+// encodeCacheValue wraps a response with the time its TTL ends.
+func encodeCacheValue(data []byte, freshUntil time.Time) ([]byte, error) {
+	return json.Marshal(staleEnvelope{FreshUntil: freshUntil, Data: data})
+}
+
+// decodeCacheValue unwraps a cached value, returning the response and the
+// time its TTL ends, or the zero time for plain values.
+func decodeCacheValue(cached []byte) ([]byte, time.Time) {
+	if bytes.HasPrefix(cached, staleEnvelopePrefix) {
+		var env staleEnvelope
+		if err := json.Unmarshal(cached, &env); err == nil {
+			return env.Data, env.FreshUntil
+		}
+	}
+	return cached, time.Time{}
+}
+
+// serveStale decodes a stale entry into result and marks it as stale. err is
+// the request error being masked, if any.
+func (c *Client) serveStale(key string, entry *staleEntry, err error, result interface{}) bool {
+	if json.Unmarshal(entry.value, result) != nil {
+		return false
+	}
+	if m, ok := result.(interface{ setCacheMeta(CacheMeta) }); ok {
+		m.setCacheMeta(CacheMeta{Stale: true, FreshUntil: entry.freshUntil, StaleErr: err})
+	}
+	c.cacheCounters.recordStale(key)
+	return true
+}
+
+// revalidate refreshes the cache entry for key in the background at low
+// priority. Only one refresh per key runs at a time, and failures leave the
+// stale entry in place. The refresh never queues for a rate limit slot,
+// even when the caller does, so Close never waits long for it.
+func (c *Client) revalidate(ctx context.Context, method, path string, body interface{}, key string, ttl time.Duration, result interface{}) {
+	if _, busy := c.revalidating.LoadOrStore(key, struct{}{}); busy {
+		return
+	}
+
+	ctx = context.WithValue(context.WithoutCancel(ctx), rateLimitQueueKey{}, false)
+	ctx = WithPriority(ctx, PriorityLow)
+
+	c.revalidateWG.Add(1)
+	go func() {
+		defer c.revalidateWG.Done()
+		defer c.revalidating.Delete(key)
+
+		_, _ = c.fetch(ctx, method, path, body, key, ttl, result)
+	}()
+}
+
+// staleOnError reports whether err is an outage or rate limit, for which a
+// stale response is better than none. Answers the API gives about the
+// request itself, such as not found, and cancellation by the caller are
+// returned as errors.
+func staleOnError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, ErrRateLimitExceeded) || errors.Is(err, ErrServerError) {
+		return true
+	}
+
+	var apiErr *APIError
+	var authErr *AuthError
+	var tokenErr *TokenError
+	return !errors.As(err, &apiErr) && !errors.As(err, &authErr) && !errors.As(err, &tokenErr)
+}
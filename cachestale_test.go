@@ -0,0 +1,206 @@
+package digikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// staleTestConfig returns a cache config with a short details TTL.
+func staleTestConfig(staleIfError, staleWhileRevalidate time.Duration) CacheConfig {
+	config := DefaultCacheConfig()
+	config.DetailsTTL = 20 * time.Millisecond
+	config.StaleIfError = staleIfError
+	config.StaleWhileRevalidate = staleWhileRevalidate
+	return config
+}
+
+// TestStaleIfError tests serving expired responses when the API fails.
+func TestStaleIfError(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			_, _ = w.Write([]byte(`{"message":"unavailable"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"P5555-ND"}}`))
+	}, WithCacheConfig(staleTestConfig(time.Minute, 0)))
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.ProductDetails(ctx, "P5555-ND")
+	if err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+	if resp.Stale {
+		t.Error("fresh response marked stale")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	status.Store(http.StatusServiceUnavailable)
+
+	resp, err = client.ProductDetails(ctx, "P5555-ND")
+	if err != nil {
+		t.Fatalf("expected stale response, got %v", err)
+	}
+	if !resp.Stale || !errors.Is(resp.StaleErr, ErrServerError) || resp.FreshUntil.IsZero() {
+		t.Errorf("unexpected cache meta %+v", resp.CacheMeta)
+	}
+	if resp.Product.DigiKeyProductNumber != "P5555-ND" {
+		t.Errorf("unexpected product %+v", resp.Product)
+	}
+	if s := client.CacheStats().Kinds["details"]; s.Stale != 1 {
+		t.Errorf("expected 1 stale response, got %+v", s)
+	}
+
+	status.Store(http.StatusNotFound)
+	if _, err := client.ProductDetails(ctx, "P5555-ND"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound to be returned, got %v", err)
+	}
+}
+
+// TestStaleIfErrorWindow tests that entries older than the window are not served.
+func TestStaleIfErrorWindow(t *testing.T) {
+	var calls atomic.Int32
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"P5555-ND"}}`))
+	}, WithCacheConfig(staleTestConfig(20*time.Millisecond, 0)))
+	defer func() { _ = client.Close() }()
+
+	ctx := context.Background()
+	if _, err := client.ProductDetails(ctx, "P5555-ND"); err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.ProductDetails(ctx, "P5555-ND"); !errors.Is(err, ErrServerError) {
+		t.Errorf("expected ErrServerError past the stale window, got %v", err)
+	}
+}
+
+// TestStaleIfErrorRateLimited tests serving stale responses when the local
+// rate limit is exhausted.
+func TestStaleIfErrorRateLimited(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"P5555-ND"}}`))
+	}, WithCacheConfig(staleTestConfig(time.Minute, 0)), WithRateLimiter(NewRateLimiterWithLimits(1, 1000)))
+	defer func() { _ = client.Close() }()
+
+	ctx := context.Background()
+	if _, err := client.ProductDetails(ctx, "P5555-ND"); err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	resp, err := client.ProductDetails(ctx, "P5555-ND")
+	if err != nil {
+		t.Fatalf("expected stale response, got %v", err)
+	}
+	if !resp.Stale || !errors.Is(resp.StaleErr, ErrRateLimitExceeded) {
+		t.Errorf("unexpected cache meta %+v", resp.CacheMeta)
+	}
+}
+
+// TestStaleWhileRevalidateQueued tests that a background refresh started
+// by a caller queueing for rate limit slots fails fast instead of queueing,
+// so Close does not wait for the next window.
+func TestStaleWhileRevalidateQueued(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"P5555-ND"}}`))
+	}, WithCacheConfig(staleTestConfig(0, time.Minute)), WithRateLimiter(NewRateLimiterWithLimits(1, 1000)))
+
+	ctx := withRateLimitQueue(context.Background())
+	if _, err := client.ProductDetails(ctx, "P5555-ND"); err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	resp, err := client.ProductDetails(ctx, "P5555-ND")
+	if err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+	if !resp.Stale {
+		t.Errorf("expected a stale response, got %+v", resp.CacheMeta)
+	}
+
+	closed := make(chan error, 1)
+	go func() { closed <- client.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close waited for the background refresh to queue")
+	}
+}
+
+// TestStaleWhileRevalidate tests returning stale responses immediately while
+// refreshing them in the background.
+func TestStaleWhileRevalidate(t *testing.T) {
+	var calls atomic.Int32
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		_, _ = fmt.Fprintf(w, `{"Product":{"DigiKeyProductNumber":"P5555-ND","QuantityAvailable":%d}}`, n)
+	}, WithCacheConfig(staleTestConfig(0, time.Minute)))
+
+	ctx := context.Background()
+	if _, err := client.ProductDetails(ctx, "P5555-ND"); err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	resp, err := client.ProductDetails(ctx, "P5555-ND")
+	if err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+	if !resp.Stale || resp.StaleErr != nil || resp.Product.QuantityAvailable != 1 {
+		t.Errorf("expected stale first response, got %+v", resp)
+	}
+
+	client.revalidateWG.Wait()
+	if calls.Load() != 2 {
+		t.Fatalf("expected a background refresh, got %d calls", calls.Load())
+	}
+
+	resp, err = client.ProductDetails(ctx, "P5555-ND")
+	if err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+	if resp.Stale || resp.Product.QuantityAvailable != 2 {
+		t.Errorf("expected refreshed response, got %+v", resp)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+// TestDecodeCacheValue tests reading plain and wrapped cache values.
+func TestDecodeCacheValue(t *testing.T) {
+	plain := []byte(`{"Product":{}}`)
+	if value, freshUntil := decodeCacheValue(plain); string(value) != string(plain) || !freshUntil.IsZero() {
+		t.Errorf("plain value decoded as %q, %v", value, freshUntil)
+	}
+
+	deadline := time.Now().Add(time.Minute).Truncate(time.Second)
+	wrapped, err := encodeCacheValue(plain, deadline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, freshUntil := decodeCacheValue(wrapped)
+	if string(value) != string(plain) || !freshUntil.Equal(deadline) {
+		t.Errorf("wrapped value decoded as %q, %v", value, freshUntil)
+	}
+}
@@ -0,0 +1,177 @@
+package digikey
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheKindStats counts cache activity for one kind of response.
+type CacheKindStats struct {
+	Hits      int64 // Lookups served from the cache, each saving an API call
+	Misses    int64 // Lookups that needed an API call
+	Evictions int64 // Entries removed to stay within size limits
+	Expired   int64 // Entries removed after their TTL
+	Stale     int64 // Expired entries served on errors or while refreshing
+	Shared    int64 // Misses answered by another caller's identical in-flight request
+	Entries   int   // Entries currently cached
+	Bytes     int64 // Size of cached keys and values
+}
+
+// HitRate returns the fraction of lookups served from the cache, or 0 if
+// there have been none.
+func (s CacheKindStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// add accumulates other into s.
+func (s *CacheKindStats) add(other CacheKindStats) {
+	s.Hits += other.Hits
+	s.Misses += other.Misses
+	s.Evictions += other.Evictions
+	s.Expired += other.Expired
+	s.Stale += other.Stale
+	s.Shared += other.Shared
+	s.Entries += other.Entries
+	s.Bytes += other.Bytes
+}
+
+// CacheStats summarizes cache activity in total and per kind of response.
+// Kinds are the cache key prefixes, such as "search", "details",
+// "manufacturers" or "pricing".
+type CacheStats struct {
+	CacheKindStats
+	Kinds map[string]CacheKindStats
+}
+
+// StatsCache is implemented by caches that report their own statistics:
+// evictions, expiries, entries and bytes. Lookups are counted by the Client,
+// which also sees stale and undecodable entries, so Client.CacheStats
+// ignores any hit, miss, stale or shared counts a cache reports.
+type StatsCache interface {
+	CacheStats() CacheStats
+}
+
+// newCacheStats totals per-kind stats into a CacheStats.
+func newCacheStats(kinds map[string]CacheKindStats) CacheStats {
+	stats := CacheStats{Kinds: kinds}
+	for _, k := range kinds {
+		stats.add(k)
+	}
+	return stats
+}
+
+// cacheKind returns the kind of a cache key: the part before the first colon,
+// after any environment prefix.
+func cacheKind(key string) string {
+	_, key = splitCacheKey(key)
+	if kind, _, ok := strings.Cut(key, ":"); ok {
+		return kind
+	}
+	return "other"
+}
+
+// cacheCounters counts a client's cache hits and misses by kind.
+type cacheCounters struct {
+	mu    sync.Mutex
+	kinds map[string]*CacheKindStats
+}
+
+// record counts a lookup of key.
+func (cc *cacheCounters) record(key string, hit bool) {
+	cc.update(key, func(s *CacheKindStats) {
+		if hit {
+			s.Hits++
+		} else {
+			s.Misses++
+		}
+	})
+}
+
+// recordStale counts a stale entry served for key.
+func (cc *cacheCounters) recordStale(key string) {
+	cc.update(key, func(s *CacheKindStats) { s.Stale++ })
+}
+
+// recordShared counts a miss answered by an in-flight request for key.
+func (cc *cacheCounters) recordShared(key string) {
+	cc.update(key, func(s *CacheKindStats) { s.Shared++ })
+}
+
+// update applies fn to the counters for key's kind.
+func (cc *cacheCounters) update(key string, fn func(*CacheKindStats)) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.kinds == nil {
+		cc.kinds = make(map[string]*CacheKindStats)
+	}
+	kind := cacheKind(key)
+	s, ok := cc.kinds[kind]
+	if !ok {
+		s = &CacheKindStats{}
+		cc.kinds[kind] = s
+	}
+	fn(s)
+}
+
+// CacheStats returns cache statistics for this client. Hits, misses, stale
+// and shared responses count lookups made by this client. Evictions, expiries,
+// entries and bytes come from the cache itself if it implements StatsCache,
+// as MemoryCache does.
+func (c *Client) CacheStats() CacheStats {
+	kinds := make(map[string]CacheKindStats)
+	if sc, ok := c.cache.(StatsCache); ok {
+		for kind, s := range sc.CacheStats().Kinds {
+			kinds[kind] = CacheKindStats{Evictions: s.Evictions, Expired: s.Expired, Entries: s.Entries, Bytes: s.Bytes}
+		}
+	}
+
+	c.cacheCounters.mu.Lock()
+	for kind, counts := range c.cacheCounters.kinds {
+		s := kinds[kind]
+		s.Hits, s.Misses, s.Stale, s.Shared = counts.Hits, counts.Misses, counts.Stale, counts.Shared
+		kinds[kind] = s
+	}
+	c.cacheCounters.mu.Unlock()
+
+	return newCacheStats(kinds)
+}
+
+// loadCache decodes the cached response for key into result if it is still
+// fresh, counting the lookup as a hit or miss. Entries that fail to decode
+// count as misses. An entry past its TTL but kept for stale serving is not
+// decoded; it is returned so the caller can fall back to it.
+func (c *Client) loadCache(key string, result interface{}) (*staleEntry, bool) {
+	if !c.cacheConfig.Enabled || c.cache == nil {
+		return nil, false
+	}
+
+	stale, hit := c.peekCache(key, result)
+	c.cacheCounters.record(key, hit)
+	return stale, hit
+}
+
+// peekCache is loadCache without counting the lookup, for callers that
+// count it themselves.
+func (c *Client) peekCache(key string, result interface{}) (*staleEntry, bool) {
+	if !c.cacheConfig.Enabled || c.cache == nil {
+		return nil, false
+	}
+
+	cached, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	value, freshUntil := decodeCacheValue(cached)
+	if !freshUntil.IsZero() && time.Now().After(freshUntil) {
+		return &staleEntry{value: value, freshUntil: freshUntil}, false
+	}
+	return nil, json.Unmarshal(value, result) == nil
+}
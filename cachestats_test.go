@@ -0,0 +1,141 @@
+package digikey
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestMemoryCacheCacheStats tests per-kind counters on MemoryCache. Lookups
+// are counted by the client, not the cache.
+func TestMemoryCacheCacheStats(t *testing.T) {
+	cache := NewMemoryCacheWithLimits(5*time.Minute, 2, 0)
+	defer func() { _ = cache.Close() }()
+
+	cache.Set("search:US:USD:a", []byte("1"), 0)
+	cache.Set("details:US:USD:b", []byte("22"), 0)
+	cache.Get("search:US:USD:a")
+	cache.Get("details:US:USD:missing")
+	cache.Set("details:US:USD:c", []byte("333"), 0) // Evicts details:b
+
+	stats := cache.CacheStats()
+	search := stats.Kinds["search"]
+	if search.Hits != 0 || search.Misses != 0 || search.Entries != 1 || search.Bytes != int64(len("search:US:USD:a")+1) {
+		t.Errorf("unexpected search stats %+v", search)
+	}
+	details := stats.Kinds["details"]
+	if details.Hits != 0 || details.Misses != 0 || details.Evictions != 1 || details.Entries != 1 {
+		t.Errorf("unexpected details stats %+v", details)
+	}
+	if stats.Entries != 2 || stats.Hits != 0 || stats.Misses != 0 || stats.Evictions != 1 {
+		t.Errorf("unexpected totals %+v", stats.CacheKindStats)
+	}
+}
+
+// TestMemoryCacheCacheStatsExpired tests counting expiries by kind.
+func TestMemoryCacheCacheStatsExpired(t *testing.T) {
+	cache := NewMemoryCache(5 * time.Minute)
+	defer func() { _ = cache.Close() }()
+
+	cache.Set("search:a", []byte("1"), 10*time.Millisecond)
+	cache.Set("details:b", []byte("2"), 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	cache.Get("search:a")
+	cache.cleanup()
+
+	stats := cache.CacheStats()
+	if s := stats.Kinds["search"]; s.Expired != 1 || s.Misses != 0 || s.Entries != 0 || s.Bytes != 0 {
+		t.Errorf("unexpected search stats %+v", s)
+	}
+	if s := stats.Kinds["details"]; s.Expired != 1 || s.Entries != 0 {
+		t.Errorf("unexpected details stats %+v", s)
+	}
+}
+
+// TestCacheKindStatsHitRate tests the hit rate calculation.
+func TestCacheKindStatsHitRate(t *testing.T) {
+	if rate := (CacheKindStats{}).HitRate(); rate != 0 {
+		t.Errorf("expected 0 for no lookups, got %v", rate)
+	}
+	if rate := (CacheKindStats{Hits: 3, Misses: 1}).HitRate(); rate != 0.75 {
+		t.Errorf("expected 0.75, got %v", rate)
+	}
+}
+
+// TestCacheKind tests extracting the kind from cache keys.
+func TestCacheKind(t *testing.T) {
+	tests := map[string]string{
+		cacheKeyForDetails(DefaultLocale(), "P5555-ND"):                   "details",
+		cacheKeyForSearch(DefaultLocale(), &SearchRequest{Keywords: "x"}): "search",
+		"nocolon": "other",
+	}
+	for key, want := range tests {
+		if got := cacheKind(key); got != want {
+			t.Errorf("cacheKind(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+// TestClientCacheStats tests that client lookups are counted and combined
+// with the cache's own figures.
+func TestClientCacheStats(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(`{"Products":[],"ProductsCount":0}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"P5555-ND"}}`))
+	})
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.ProductDetails(ctx, "P5555-ND"); err != nil {
+			t.Fatalf("ProductDetails: %v", err)
+		}
+	}
+	if _, err := client.KeywordSearch(ctx, &SearchRequest{Keywords: "resistor"}); err != nil {
+		t.Fatalf("KeywordSearch: %v", err)
+	}
+
+	stats := client.CacheStats()
+	if s := stats.Kinds["details"]; s.Hits != 2 || s.Misses != 1 || s.Entries != 1 || s.Bytes == 0 {
+		t.Errorf("unexpected details stats %+v", s)
+	}
+	if s := stats.Kinds["search"]; s.Hits != 0 || s.Misses != 1 || s.Entries != 1 {
+		t.Errorf("unexpected search stats %+v", s)
+	}
+	if stats.Hits != 2 || stats.Misses != 2 {
+		t.Errorf("unexpected totals %+v", stats.CacheKindStats)
+	}
+}
+
+// TestClientCacheStatsCustomCache tests hit and miss counting with a cache
+// that does not report its own statistics.
+func TestClientCacheStatsCustomCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"P5555-ND"}}`))
+	}, WithCache(cache))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ProductDetails(ctx, "P5555-ND"); err != nil {
+			t.Fatalf("ProductDetails: %v", err)
+		}
+	}
+
+	if s := client.CacheStats().Kinds["details"]; s.Hits != 1 || s.Misses != 1 || s.Entries != 0 {
+		t.Errorf("unexpected details stats %+v", s)
+	}
+}
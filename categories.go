@@ -0,0 +1,183 @@
+package digikey
+
+import (
+	"context"
+	"strings"
+)
+
+// CategoryTree is an indexed view of the Digi-Key category hierarchy.
+type CategoryTree struct {
+	roots  []Category
+	byID   map[int]*Category
+	parent map[int]int
+}
+
+// NewCategoryTree builds a tree from top-level categories and their children.
+func NewCategoryTree(categories []Category) *CategoryTree {
+	t := &CategoryTree{
+		roots:  categories,
+		byID:   make(map[int]*Category),
+		parent: make(map[int]int),
+	}
+
+	var index func(cats []Category, parentID int)
+	index = func(cats []Category, parentID int) {
+		for i := range cats {
+			cat := &cats[i]
+			t.byID[cat.CategoryID] = cat
+			switch {
+			case parentID != 0:
+				t.parent[cat.CategoryID] = parentID
+			case cat.ParentID != 0:
+				t.parent[cat.CategoryID] = cat.ParentID
+			}
+			index(cat.ChildCategories, cat.CategoryID)
+		}
+	}
+	index(t.roots, 0)
+
+	return t
+}
+
+// CategoryTree retrieves all categories and returns them as a CategoryTree.
+func (c *Client) CategoryTree(ctx context.Context) (*CategoryTree, error) {
+	resp, err := c.Categories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewCategoryTree(resp.Categories), nil
+}
+
+// Roots returns the top-level categories.
+func (t *CategoryTree) Roots() []Category {
+	return t.roots
+}
+
+// Len returns the total number of categories in the tree.
+func (t *CategoryTree) Len() int {
+	return len(t.byID)
+}
+
+// Walk visits every category depth-first, parents before children.
+// Depth is 0 for top-level categories. Returning false from fn stops the walk.
+func (t *CategoryTree) Walk(fn func(cat Category, depth int) bool) {
+	var walk func(cats []Category, depth int) bool
+	walk = func(cats []Category, depth int) bool {
+		for _, cat := range cats {
+			if !fn(cat, depth) {
+				return false
+			}
+			if !walk(cat.ChildCategories, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(t.roots, 0)
+}
+
+// Find returns the category with the given ID.
+func (t *CategoryTree) Find(categoryID int) (Category, bool) {
+	cat, ok := t.byID[categoryID]
+	if !ok {
+		return Category{}, false
+	}
+	return *cat, true
+}
+
+// FindByName returns all categories whose name matches, ignoring case.
+// Names are not unique across the hierarchy, so more than one category may be returned.
+func (t *CategoryTree) FindByName(name string) []Category {
+	name = strings.TrimSpace(name)
+
+	var matches []Category
+	t.Walk(func(cat Category, _ int) bool {
+		if strings.EqualFold(cat.Name, name) {
+			matches = append(matches, cat)
+		}
+		return true
+	})
+	return matches
+}
+
+// FindByPath returns the category reached by following names from the top
+// level down, ignoring case. For example:
+//
+//	tree.FindByPath("Integrated Circuits (ICs)", "Embedded", "Microcontrollers")
+func (t *CategoryTree) FindByPath(names ...string) (Category, bool) {
+	if len(names) == 0 {
+		return Category{}, false
+	}
+
+	level := t.roots
+	var found *Category
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		found = nil
+		for i := range level {
+			if strings.EqualFold(level[i].Name, name) {
+				found = &level[i]
+				break
+			}
+		}
+		if found == nil {
+			return Category{}, false
+		}
+		level = found.ChildCategories
+	}
+
+	return *found, true
+}
+
+// Breadcrumbs returns the path from the top-level category down to cat.
+//
+// cat may be a Product.Category, in which case the deepest category of its
+// ChildCategories chain is used. If the category is not in the tree, the
+// chain from cat itself is returned.
+func (t *CategoryTree) Breadcrumbs(cat Category) []Category {
+	chain := []Category{cat}
+	for len(chain[len(chain)-1].ChildCategories) > 0 {
+		chain = append(chain, chain[len(chain)-1].ChildCategories[0])
+	}
+
+	leaf := chain[len(chain)-1]
+	if _, ok := t.byID[leaf.CategoryID]; !ok {
+		return chain
+	}
+
+	var crumbs []Category
+	seen := make(map[int]bool)
+	for id := leaf.CategoryID; id != 0 && !seen[id]; id = t.parent[id] {
+		seen[id] = true
+		node, ok := t.byID[id]
+		if !ok {
+			break
+		}
+		crumbs = append(crumbs, *node)
+	}
+
+	for i, j := 0, len(crumbs)-1; i < j; i, j = i+1, j-1 {
+		crumbs[i], crumbs[j] = crumbs[j], crumbs[i]
+	}
+	return crumbs
+}
+
+// Path returns the category names from the top level down to cat.
+func (t *CategoryTree) Path(cat Category) []string {
+	crumbs := t.Breadcrumbs(cat)
+	names := make([]string, len(crumbs))
+	for i, c := range crumbs {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Flatten returns every category in the tree in depth-first order.
+func (t *CategoryTree) Flatten() []Category {
+	flat := make([]Category, 0, len(t.byID))
+	t.Walk(func(cat Category, _ int) bool {
+		flat = append(flat, cat)
+		return true
+	})
+	return flat
+}
@@ -0,0 +1,195 @@
+package digikey
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func testCategories() []Category {
+	return []Category{
+		{
+			CategoryID: 32,
+			Name:       "Integrated Circuits (ICs)",
+			ChildCategories: []Category{
+				{
+					CategoryID: 2012,
+					ParentID:   32,
+					Name:       "Embedded",
+					ChildCategories: []Category{
+						{CategoryID: 685, ParentID: 2012, Name: "Microcontrollers"},
+						{CategoryID: 694, ParentID: 2012, Name: "FPGAs (Field Programmable Gate Array)"},
+					},
+				},
+			},
+		},
+		{
+			CategoryID: 2,
+			Name:       "Resistors",
+			ChildCategories: []Category{
+				{CategoryID: 52, ParentID: 2, Name: "Chip Resistor - Surface Mount"},
+			},
+		},
+	}
+}
+
+// TestCategoryTreeFind tests lookup by ID.
+func TestCategoryTreeFind(t *testing.T) {
+	tree := NewCategoryTree(testCategories())
+
+	if tree.Len() != 6 {
+		t.Errorf("expected 6 categories, got %d", tree.Len())
+	}
+
+	cat, ok := tree.Find(685)
+	if !ok {
+		t.Fatal("expected to find category 685")
+	}
+	if cat.Name != "Microcontrollers" {
+		t.Errorf("expected Microcontrollers, got %s", cat.Name)
+	}
+
+	if _, ok := tree.Find(9999); ok {
+		t.Error("expected unknown category to be missing")
+	}
+}
+
+// TestCategoryTreeFindByName tests case-insensitive name lookup.
+func TestCategoryTreeFindByName(t *testing.T) {
+	tree := NewCategoryTree(testCategories())
+
+	matches := tree.FindByName("  microcontrollers ")
+	if len(matches) != 1 || matches[0].CategoryID != 685 {
+		t.Errorf("expected category 685, got %+v", matches)
+	}
+
+	if matches := tree.FindByName("Capacitors"); len(matches) != 0 {
+		t.Errorf("expected no matches, got %d", len(matches))
+	}
+}
+
+// TestCategoryTreeFindByPath tests lookup by name path.
+func TestCategoryTreeFindByPath(t *testing.T) {
+	tree := NewCategoryTree(testCategories())
+
+	cat, ok := tree.FindByPath("Integrated Circuits (ICs)", "embedded", "Microcontrollers")
+	if !ok {
+		t.Fatal("expected to find path")
+	}
+	if cat.CategoryID != 685 {
+		t.Errorf("expected category 685, got %d", cat.CategoryID)
+	}
+
+	if _, ok := tree.FindByPath("Integrated Circuits (ICs)", "Microcontrollers"); ok {
+		t.Error("expected incomplete path to fail")
+	}
+	if _, ok := tree.FindByPath(); ok {
+		t.Error("expected empty path to fail")
+	}
+}
+
+// TestCategoryTreeBreadcrumbs tests breadcrumbs for a product category chain.
+func TestCategoryTreeBreadcrumbs(t *testing.T) {
+	tree := NewCategoryTree(testCategories())
+
+	// Product categories arrive as a chain from the top level to the leaf
+	productCategory := Category{
+		CategoryID: 32,
+		Name:       "Integrated Circuits (ICs)",
+		ChildCategories: []Category{
+			{CategoryID: 2012, Name: "Embedded", ChildCategories: []Category{
+				{CategoryID: 685, Name: "Microcontrollers"},
+			}},
+		},
+	}
+
+	path := tree.Path(productCategory)
+	expected := []string{"Integrated Circuits (ICs)", "Embedded", "Microcontrollers"}
+	if len(path) != len(expected) {
+		t.Fatalf("expected path %v, got %v", expected, path)
+	}
+	for i := range expected {
+		if path[i] != expected[i] {
+			t.Errorf("path[%d]: expected %s, got %s", i, expected[i], path[i])
+		}
+	}
+
+	// A leaf category alone resolves through the tree
+	crumbs := tree.Breadcrumbs(Category{CategoryID: 52})
+	if len(crumbs) != 2 || crumbs[0].CategoryID != 2 {
+		t.Errorf("expected breadcrumbs Resistors > Chip Resistor, got %+v", crumbs)
+	}
+
+	// Unknown categories fall back to their own chain
+	crumbs = tree.Breadcrumbs(Category{CategoryID: 7, Name: "Unknown"})
+	if len(crumbs) != 1 || crumbs[0].Name != "Unknown" {
+		t.Errorf("expected fallback breadcrumb, got %+v", crumbs)
+	}
+}
+
+// TestCategoryTreeWalk tests depth-first traversal and early stop.
+func TestCategoryTreeWalk(t *testing.T) {
+	tree := NewCategoryTree(testCategories())
+
+	var depths []int
+	tree.Walk(func(cat Category, depth int) bool {
+		depths = append(depths, depth)
+		return true
+	})
+	expected := []int{0, 1, 2, 2, 0, 1}
+	if len(depths) != len(expected) {
+		t.Fatalf("expected depths %v, got %v", expected, depths)
+	}
+	for i := range expected {
+		if depths[i] != expected[i] {
+			t.Errorf("depth[%d]: expected %d, got %d", i, expected[i], depths[i])
+		}
+	}
+
+	visited := 0
+	tree.Walk(func(cat Category, depth int) bool {
+		visited++
+		return visited < 3
+	})
+	if visited != 3 {
+		t.Errorf("expected walk to stop after 3 categories, got %d", visited)
+	}
+}
+
+// TestCategoryTreeFlatten tests flattening the tree.
+func TestCategoryTreeFlatten(t *testing.T) {
+	tree := NewCategoryTree(testCategories())
+
+	flat := tree.Flatten()
+	if len(flat) != 6 {
+		t.Fatalf("expected 6 categories, got %d", len(flat))
+	}
+	if flat[0].CategoryID != 32 || flat[5].CategoryID != 52 {
+		t.Errorf("unexpected flatten order: first %d, last %d", flat[0].CategoryID, flat[5].CategoryID)
+	}
+}
+
+// TestClientCategoryTree tests fetching the category tree from the API.
+func TestClientCategoryTree(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/products/v4/search/categories" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"ProductCount":10,"Categories":[{"CategoryId":2,"Name":"Resistors","ChildCategories":[{"CategoryId":52,"ParentId":2,"Name":"Chip Resistor - Surface Mount"}]}]}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tree, err := client.CategoryTree(ctx)
+	if err != nil {
+		t.Fatalf("CategoryTree failed: %v", err)
+	}
+	if len(tree.Roots()) != 1 {
+		t.Errorf("expected 1 root, got %d", len(tree.Roots()))
+	}
+	if _, ok := tree.Find(52); !ok {
+		t.Error("expected to find category 52")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,16 +19,29 @@ const (
 
 // Client is the Digi-Key API client.
 type Client struct {
-	httpClient   *http.Client
-	baseURL      string
-	clientID     string
-	tokenManager *tokenManager
-	rateLimiter  *RateLimiter
-	retryConfig  RetryConfig
-	cache        Cache
-	cacheConfig  CacheConfig
-	locale       Locale
-	localeMu     sync.RWMutex
+	httpClient    *http.Client
+	baseURL       string
+	clientID      string
+	environment   Environment
+	tokenManager  *tokenManager
+	tokenRefresh  *TokenRefreshConfig // Set by WithBackgroundTokenRefresh
+	stopRefresh   func()
+	rateLimiter   Limiter
+	rateWait      time.Duration
+	retryConfig   RetryConfig
+	cache         Cache
+	ownsCache     bool // cache was created by NewClient and is closed by Close
+	cacheConfig   CacheConfig
+	cacheCounters cacheCounters
+	revalidating  sync.Map // Keys being refreshed in the background
+	revalidateWG  sync.WaitGroup
+	inflight      flightGroup
+	locale        Locale
+	localeMu      sync.RWMutex
+
+	manufacturerMu          sync.Mutex
+	manufacturerIndex       *ManufacturerIndex
+	manufacturerIndexExpiry time.Time
 }
 
 // ClientOption configures a Client.
@@ -54,19 +68,61 @@ func WithLocale(locale Locale) ClientOption {
 	}
 }
 
-// WithRateLimiter sets a custom rate limiter.
-func WithRateLimiter(rateLimiter *RateLimiter) ClientOption {
+// WithRateLimiter sets a custom rate limiter, such as a SlidingWindowLimiter
+// or TokenBucketLimiter. The default is a fixed-window RateLimiter.
+func WithRateLimiter(rateLimiter Limiter) ClientOption {
 	return func(c *Client) {
 		c.rateLimiter = rateLimiter
 	}
 }
 
+// WithRateLimitWait makes requests wait up to maxWait for the rate limiter
+// instead of failing immediately with a RateLimitError. Waiting requests are
+// served in arrival order.
+func WithRateLimitWait(maxWait time.Duration) ClientOption {
+	return func(c *Client) {
+		c.rateWait = maxWait
+	}
+}
+
 // WithTokenURL sets a custom token URL (useful for testing).
 func WithTokenURL(tokenURL string) ClientOption {
 	return func(c *Client) {
-		if c.tokenManager != nil {
-			c.tokenManager.tokenURL = tokenURL
-		}
+		c.tokenManager.tokenURL = tokenURL
+	}
+}
+
+// WithAuthorizeURL sets a custom OAuth2 authorization URL (useful for testing).
+func WithAuthorizeURL(authorizeURL string) ClientOption {
+	return func(c *Client) {
+		c.tokenManager.authorizeURL = authorizeURL
+	}
+}
+
+// WithAuthorizationCode enables the three-legged OAuth flow, which acts on
+// behalf of a Digi-Key customer account. redirectURL must match the callback
+// registered for the app. Requests fail with ErrAuthorizationRequired until
+// the client has tokens from ExchangeCode, AuthorizeLoopback or WithToken.
+func WithAuthorizationCode(redirectURL string) ClientOption {
+	return func(c *Client) {
+		c.tokenManager.authCode = true
+		c.tokenManager.redirectURL = redirectURL
+	}
+}
+
+// WithToken resumes a three-legged OAuth session from a token saved with
+// Client.Token. The access token is renewed with its refresh token.
+func WithToken(token Token) ClientOption {
+	return func(c *Client) {
+		c.tokenManager.restore(token)
+	}
+}
+
+// WithTokenStore persists OAuth2 tokens in store, so new clients and other
+// processes sharing it reuse a valid token instead of fetching their own.
+func WithTokenStore(store TokenStore) ClientOption {
+	return func(c *Client) {
+		c.tokenManager.store = store
 	}
 }
 
@@ -119,19 +175,23 @@ func NewClient(clientID, clientSecret string, opts ...ClientOption) *Client {
 		cacheConfig: cacheConfig,
 	}
 
+	// Options configure the token manager directly; it picks up the final
+	// HTTP client and retry configuration afterwards
+	c.tokenManager = newTokenManager(c.httpClient, clientID, clientSecret, "")
 	for _, opt := range opts {
 		opt(c)
 	}
-
-	c.tokenManager = newTokenManager(c.httpClient, clientID, clientSecret, "")
+	c.tokenManager.httpClient = c.httpClient
+	c.tokenManager.retry = c.retryConfig
 
 	// Initialize default cache if caching is enabled and no custom cache was provided
 	if c.cacheConfig.Enabled && c.cache == nil {
-		c.cache = NewMemoryCache(c.cacheConfig.DetailsTTL)
+		c.cache = NewMemoryCacheWithLimits(c.cacheConfig.DetailsTTL, c.cacheConfig.MaxEntries, c.cacheConfig.MaxBytes)
+		c.ownsCache = true
 	}
 
-	for _, opt := range opts {
-		opt(c)
+	if c.tokenRefresh != nil {
+		c.stopRefresh = c.tokenManager.startRefresher(*c.tokenRefresh)
 	}
 
 	return c
@@ -156,10 +216,35 @@ func (c *Client) RateLimitStats() RateLimitStats {
 	return c.rateLimiter.Stats()
 }
 
-// ClearCache clears all cached responses.
+// Close releases resources held by the client, such as the default cache's
+// cleanup goroutine and the background token refresher, after waiting for
+// background cache refreshes. Caches passed with WithCache are not closed.
+// The client must not be used after Close.
+func (c *Client) Close() error {
+	if c.stopRefresh != nil {
+		c.stopRefresh()
+	}
+	c.revalidateWG.Wait()
+	if closer, ok := c.cache.(io.Closer); ok && c.ownsCache {
+		return closer.Close()
+	}
+	return nil
+}
+
+// ClearCache clears all cached responses from the client's environment.
+// With an InvalidatingCache, as MemoryCache and DiskCache are, responses
+// cached by clients for other environments are kept. A cache that only
+// implements ClearableCache is cleared entirely, whatever the environment;
+// other caches are left unchanged.
 func (c *Client) ClearCache() {
-	if mc, ok := c.cache.(*MemoryCache); ok {
-		mc.Clear()
+	switch cache := c.cache.(type) {
+	case InvalidatingCache:
+		cache.DeleteMatching(func(key string) bool {
+			env, _ := splitCacheKey(key)
+			return env == c.environment
+		})
+	case ClearableCache:
+		cache.Clear()
 	}
 }
 
@@ -168,6 +253,68 @@ func (c *Client) do(ctx context.Context, method, path string, body interface{},
 	return c.doWithRetry(ctx, method, path, body, result, false)
 }
 
+// cachedDo performs a request through do, serving the response from the cache
+// when an entry exists for key and storing successful responses for ttl.
+// Entries past their TTL are served as stale responses within the
+// configured StaleWhileRevalidate and StaleIfError windows. Concurrent
+// requests for the same key share one API call.
+func (c *Client) cachedDo(ctx context.Context, method, path string, body interface{}, key string, ttl time.Duration, result interface{}) error {
+	stale, hit := c.loadCache(key, result)
+	if hit {
+		return nil
+	}
+
+	if stale.within(c.cacheConfig.StaleWhileRevalidate) && c.serveStale(key, stale, nil, result) {
+		c.revalidate(ctx, method, path, body, key, ttl, result)
+		return nil
+	}
+
+	data, shared, err := c.inflight.do(ctx, flightKey(ctx, key), func(ctx context.Context) ([]byte, error) {
+		return c.fetch(ctx, method, path, body, key, ttl, result)
+	})
+	if err != nil {
+		if stale.within(c.cacheConfig.StaleIfError) && staleOnError(ctx, err) && c.serveStale(key, stale, err, result) {
+			return nil
+		}
+		return err
+	}
+	if shared {
+		c.cacheCounters.recordShared(key)
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("digikey: failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// storeCache stores a response in the cache if caching is enabled. When
+// stale serving is configured, the entry is kept past ttl for that long.
+func (c *Client) storeCache(key string, ttl time.Duration, result interface{}) {
+	if !c.cacheConfig.Enabled || c.cache == nil {
+		return
+	}
+	if data, err := json.Marshal(result); err == nil {
+		c.storeCacheData(key, ttl, data)
+	}
+}
+
+// storeCacheData stores an encoded response in the cache if caching is
+// enabled, like storeCache.
+func (c *Client) storeCacheData(key string, ttl time.Duration, data []byte) {
+	if !c.cacheConfig.Enabled || c.cache == nil {
+		return
+	}
+	if window := c.cacheConfig.staleWindow(); window > 0 && ttl > 0 {
+		var err error
+		if data, err = encodeCacheValue(data, time.Now().Add(ttl)); err != nil {
+			return
+		}
+		ttl += window
+	}
+	c.cache.Set(key, data, ttl)
+}
+
 // doWithRetry performs an HTTP request with retry logic.
 func (c *Client) doWithRetry(ctx context.Context, method, path string, body interface{}, result interface{}, isRetryAfter401 bool) error {
 	var lastErr error
@@ -190,7 +337,7 @@ func (c *Client) doWithRetry(ctx context.Context, method, path string, body inte
 
 		// Handle 401: refresh token and retry once
 		if statusCode == http.StatusUnauthorized && !isRetryAfter401 {
-			c.tokenManager.invalidate()
+			c.tokenManager.invalidate(ctx)
 			return c.doWithRetry(ctx, method, path, body, result, true)
 		}
 
@@ -211,7 +358,7 @@ func (c *Client) doWithRetry(ctx context.Context, method, path string, body inte
 // doOnce performs a single HTTP request attempt.
 // Returns (error, statusCode, shouldRetry).
 func (c *Client) doOnce(ctx context.Context, method, path string, body interface{}, result interface{}) (int, bool, error) {
-	if err := c.rateLimiter.Allow(); err != nil {
+	if err := c.acquireRateLimit(ctx); err != nil {
 		return 0, false, err
 	}
 
@@ -249,6 +396,12 @@ func (c *Client) doOnce(ctx context.Context, method, path string, body interface
 		return resp.StatusCode, false, fmt.Errorf("digikey: failed to read response: %w", err)
 	}
 
+	if limit, ok := parseRateLimitHeaders(resp.Header); ok {
+		if syncer, ok := c.rateLimiter.(RateLimitSyncer); ok {
+			syncer.SyncFromServer(limit)
+		}
+	}
+
 	// Handle rate limiting (429)
 	if resp.StatusCode == http.StatusTooManyRequests {
 		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
@@ -283,6 +436,49 @@ func (c *Client) doOnce(ctx context.Context, method, path string, body interface
 	return resp.StatusCode, false, nil
 }
 
+// rateLimitQueueKey is the context key set by withRateLimitQueue.
+type rateLimitQueueKey struct{}
+
+// rateLimitQueueWait bounds how long requests from withRateLimitQueue wait
+// for a slot. It is long enough for the minute window to reset, but not for
+// the day window, so an exhausted day budget fails with a RateLimitError.
+const rateLimitQueueWait = 2 * time.Minute
+
+// withRateLimitQueue returns a context whose requests queue for a rate
+// limit slot, for up to rateLimitQueueWait or WithRateLimitWait's duration
+// if that is longer, instead of failing when none is free. Bulk helpers
+// such as SearchIterator and ProductDetailsBatch use it so that they pace
+// themselves.
+func withRateLimitQueue(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rateLimitQueueKey{}, true)
+}
+
+// acquireRateLimit reserves a request slot at the context's priority,
+// waiting for one if WithRateLimitWait is set or the context comes from
+// withRateLimitQueue.
+func (c *Client) acquireRateLimit(ctx context.Context) error {
+	maxWait := c.rateWait
+	if queued, _ := ctx.Value(rateLimitQueueKey{}).(bool); queued {
+		maxWait = max(maxWait, rateLimitQueueWait)
+	}
+
+	if maxWait <= 0 {
+		if pa, ok := c.rateLimiter.(priorityAllower); ok {
+			return pa.AllowPriority(PriorityFromContext(ctx))
+		}
+		return c.rateLimiter.Allow()
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, maxWait)
+	defer cancel()
+
+	err := c.rateLimiter.Wait(waitCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: no request slot within %s", ErrRateLimitExceeded, maxWait)
+	}
+	return err
+}
+
 // setHeaders sets the required headers for Digi-Key API requests.
 func (c *Client) setHeaders(req *http.Request, token string, locale Locale) {
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -2,7 +2,9 @@ package digikey
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 )
@@ -166,3 +168,147 @@ func TestContextTimeout(t *testing.T) {
 		t.Fatal("expected error")
 	}
 }
+
+// newMockClient creates a client whose API and token endpoints are served by handler.
+// Token requests are answered automatically.
+func newMockClient(t *testing.T, handler http.HandlerFunc, opts ...ClientOption) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oauth2/token" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	opts = append([]ClientOption{
+		WithBaseURL(server.URL),
+		WithTokenURL(server.URL + "/v1/oauth2/token"),
+		WithoutRetry(),
+	}, opts...)
+	return NewClient("test-id", "test-secret", opts...)
+}
+
+// TestCachedDoServesFromCache tests that cached responses skip the network.
+func TestCachedDoServesFromCache(t *testing.T) {
+	calls := 0
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"Manufacturers":[{"Id":1,"Name":"Texas Instruments"}]}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		var resp ManufacturersResponse
+		if err := client.cachedGet(ctx, "manufacturers", "/products/v4/search/manufacturers", &resp); err != nil {
+			t.Fatalf("cachedGet failed: %v", err)
+		}
+		if len(resp.Manufacturers) != 1 {
+			t.Fatalf("expected 1 manufacturer, got %d", len(resp.Manufacturers))
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected 1 API call, got %d", calls)
+	}
+}
+
+// TestClientRateLimitWait tests that WithRateLimitWait waits for a slot
+// instead of failing, and gives up after maxWait.
+func TestClientRateLimitWait(t *testing.T) {
+	limiter := NewRateLimiterWithLimits(1, 1000)
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Product":{}}`))
+	}, WithoutCache(), WithRateLimiter(limiter), WithRateLimitWait(time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.ProductDetails(ctx, "A"); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+
+	// Open the next slot shortly
+	limiter.mu.Lock()
+	limiter.minuteResetTime = time.Now().Add(50 * time.Millisecond)
+	limiter.mu.Unlock()
+
+	if _, err := client.ProductDetails(ctx, "B"); err != nil {
+		t.Fatalf("expected request to wait for a slot, got %v", err)
+	}
+
+	// The next slot is a minute away, beyond maxWait
+	start := time.Now()
+	_, err := client.ProductDetails(ctx, "C")
+	if !errors.Is(err, ErrRateLimitExceeded) {
+		t.Fatalf("expected ErrRateLimitExceeded, got %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("expected to fail without waiting the full maxWait")
+	}
+}
+
+// TestClientSyncsRateLimitHeaders tests that response headers update the limiter.
+func TestClientSyncsRateLimitHeaders(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "1000")
+		w.Header().Set("X-RateLimit-Remaining", "400")
+		w.Header().Set("X-BurstLimit-Limit", "120")
+		w.Header().Set("X-BurstLimit-Remaining", "0")
+		_, _ = w.Write([]byte(`{"Product":{}}`))
+	}, WithoutCache())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.ProductDetails(ctx, "A"); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	stats := client.RateLimitStats()
+	if stats.DayUsed != 600 || stats.MinuteRemaining != 0 {
+		t.Errorf("expected stats from response headers, got %+v", stats)
+	}
+	if _, err := client.ProductDetails(ctx, "B"); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("expected exhausted burst limit to block requests, got %v", err)
+	}
+}
+
+// TestClientCacheLimitsAndClose tests the default cache's limits and Close.
+func TestClientCacheLimitsAndClose(t *testing.T) {
+	config := DefaultCacheConfig()
+	config.MaxEntries = 10
+	client := NewClient("id", "secret", WithCacheConfig(config))
+
+	mc, ok := client.cache.(*MemoryCache)
+	if !ok {
+		t.Fatalf("expected default MemoryCache, got %T", client.cache)
+	}
+	if mc.Stats().MaxEntries != 10 {
+		t.Errorf("expected MaxEntries from config, got %d", mc.Stats().MaxEntries)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case <-mc.done:
+	default:
+		t.Error("expected default cache to be closed")
+	}
+
+	// Caches passed in are left to the caller
+	custom := NewMemoryCache(time.Minute)
+	defer func() { _ = custom.Close() }()
+	_ = NewClient("id", "secret", WithCache(custom)).Close()
+	select {
+	case <-custom.done:
+		t.Error("custom cache should not be closed by the client")
+	default:
+	}
+}
@@ -0,0 +1,107 @@
+package digikey
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// flightGroup coalesces concurrent identical requests so they share one API
+// call and one rate limit slot.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight is a call in progress for one key.
+type flight struct {
+	done    chan struct{}
+	data    []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// do runs fn once for all concurrent callers with the same key and returns
+// its result to each of them. shared reports whether the call was started by
+// another caller.
+//
+// fn runs with a context that keeps ctx's values, such as the request
+// priority, and is canceled once every waiting caller has given up. A caller
+// whose context ends stops waiting without affecting the others.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) ([]byte, error)) (data []byte, shared bool, err error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = make(map[string]*flight)
+	}
+	f, shared := g.flights[key]
+	if !shared {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+
+		go func() {
+			defer cancel()
+			f.data, f.err = fn(callCtx)
+
+			g.mu.Lock()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.data, shared, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Later callers must start a new call rather than join this
+			// canceled one.
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			f.cancel()
+		}
+		g.mu.Unlock()
+		return nil, shared, ctx.Err()
+	}
+}
+
+// flightKey returns the key under which requests for the cache entry key
+// share a call. The call takes its rate limit slot as the caller that
+// started it would, so only requests with the same priority and queueing
+// behaviour share one; a high-priority or fail-fast request never waits
+// behind a queued bulk request.
+func flightKey(ctx context.Context, key string) string {
+	key += "#" + strconv.Itoa(int(PriorityFromContext(ctx)))
+	if queued, _ := ctx.Value(rateLimitQueueKey{}).(bool); queued {
+		key += "#queued"
+	}
+	return key
+}
+
+// fetch performs a request into a new value of result's type, caches it for
+// ttl and returns it as JSON. result itself is not modified.
+func (c *Client) fetch(ctx context.Context, method, path string, body interface{}, key string, ttl time.Duration, result interface{}) ([]byte, error) {
+	fresh := reflect.New(reflect.TypeOf(result).Elem()).Interface()
+	if err := c.do(ctx, method, path, body, fresh); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(fresh)
+	if err != nil {
+		return nil, err
+	}
+	c.storeCacheData(key, ttl, data)
+	return data, nil
+}
@@ -0,0 +1,268 @@
+package digikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters blocks until n callers are waiting on the flight for key.
+func waitForWaiters(t *testing.T, g *flightGroup, key string, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		f := g.flights[key]
+		waiters := 0
+		if f != nil {
+			waiters = f.waiters
+		}
+		g.mu.Unlock()
+
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d waiters on %q", n, key)
+}
+
+// TestProductDetailsCoalescing tests that concurrent identical requests share
+// one API call.
+func TestProductDetailsCoalescing(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"497-15360-ND"}}`))
+	})
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.ProductDetails(ctx, "497-15360-ND")
+			if err == nil && resp.Product.DigiKeyProductNumber != "497-15360-ND" {
+				err = errors.New("unexpected product " + resp.Product.DigiKeyProductNumber)
+			}
+			errs <- err
+		}()
+	}
+
+	key := flightKey(ctx, cacheKeyForDetails(client.getLocale(), "497-15360-ND"))
+	waitForWaiters(t, &client.inflight, key, callers)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("ProductDetails: %v", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 API call, got %d", calls.Load())
+	}
+	if s := client.CacheStats().Kinds["details"]; s.Shared != callers-1 {
+		t.Errorf("expected %d shared responses, got %+v", callers-1, s)
+	}
+	if stats := client.RateLimitStats(); stats.MinuteRemaining != stats.MinuteLimit-1 {
+		t.Errorf("expected one rate limit slot used, got %+v", stats)
+	}
+}
+
+// TestCoalescingCallerCancel tests that a caller giving up does not cancel
+// the call for the others.
+func TestCoalescingCallerCancel(t *testing.T) {
+	release := make(chan struct{})
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"Products":[],"ProductsCount":7}`))
+	}, WithoutCache())
+	defer func() { _ = client.Close() }()
+
+	req := &SearchRequest{Keywords: "resistor", Limit: 10}
+	key := flightKey(context.Background(), cacheKeyForSearch(client.getLocale(), req))
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := client.KeywordSearch(firstCtx, req)
+		firstErr <- err
+	}()
+	waitForWaiters(t, &client.inflight, key, 1)
+
+	type result struct {
+		resp *SearchResponse
+		err  error
+	}
+	second := make(chan result, 1)
+	go func() {
+		resp, err := client.KeywordSearch(context.Background(), req)
+		second <- result{resp, err}
+	}()
+	waitForWaiters(t, &client.inflight, key, 2)
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for the first caller, got %v", err)
+	}
+
+	close(release)
+	r := <-second
+	if r.err != nil {
+		t.Fatalf("second caller: %v", r.err)
+	}
+	if r.resp.ProductsCount != 7 {
+		t.Errorf("unexpected response %+v", r.resp)
+	}
+}
+
+// TestFlightGroupCancelsAbandonedCall tests that the shared call is canceled
+// once every caller has given up.
+func TestFlightGroupCancelsAbandonedCall(t *testing.T) {
+	var g flightGroup
+	canceled := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, shared, err := g.do(ctx, "key", func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		close(canceled)
+		return nil, ctx.Err()
+	})
+	if shared || !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected result shared=%v err=%v", shared, err)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("abandoned call was not canceled")
+	}
+}
+
+// TestFlightGroupRestartsAbandonedCall tests that a caller arriving after
+// every earlier caller gave up starts a new call instead of joining the
+// canceled one, even while the canceled call is still returning.
+func TestFlightGroupRestartsAbandonedCall(t *testing.T) {
+	var g flightGroup
+	release := make(chan struct{})
+	firstDone := make(chan struct{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, _, err := g.do(ctx, "key", func(ctx context.Context) ([]byte, error) {
+			defer close(firstDone)
+			<-ctx.Done()
+			<-release
+			return nil, ctx.Err()
+		})
+		firstErr <- err
+	}()
+	waitForWaiters(t, &g, "key", 1)
+
+	cancel()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled for the first caller, got %v", err)
+	}
+
+	type result struct {
+		data   []byte
+		shared bool
+		err    error
+	}
+	secondRelease := make(chan struct{})
+	second := make(chan result, 1)
+	go func() {
+		data, shared, err := g.do(context.Background(), "key", func(ctx context.Context) ([]byte, error) {
+			<-secondRelease
+			return []byte("ok"), nil
+		})
+		second <- result{data, shared, err}
+	}()
+	waitForWaiters(t, &g, "key", 1)
+
+	// The canceled call finishing must not remove the second caller's flight.
+	close(release)
+	<-firstDone
+	third := make(chan result, 1)
+	go func() {
+		data, shared, err := g.do(context.Background(), "key", func(ctx context.Context) ([]byte, error) {
+			return nil, errors.New("unexpected new call")
+		})
+		third <- result{data, shared, err}
+	}()
+	waitForWaiters(t, &g, "key", 2)
+	close(secondRelease)
+
+	r := <-second
+	if r.err != nil || r.shared || string(r.data) != "ok" {
+		t.Errorf("unexpected second result data=%q shared=%v err=%v", r.data, r.shared, r.err)
+	}
+	r = <-third
+	if r.err != nil || !r.shared || string(r.data) != "ok" {
+		t.Errorf("unexpected third result data=%q shared=%v err=%v", r.data, r.shared, r.err)
+	}
+}
+
+// TestCoalescingByPriority tests that requests only share a call with
+// requests of the same priority and queueing behaviour.
+func TestCoalescingByPriority(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"P1"}}`))
+	}, WithoutCache())
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	contexts := []context.Context{
+		withRateLimitQueue(WithPriority(ctx, PriorityLow)),
+		WithPriority(ctx, PriorityLow),
+		WithPriority(ctx, PriorityHigh),
+	}
+	errs := make(chan error, len(contexts))
+	for _, ctx := range contexts {
+		go func() {
+			_, err := client.ProductDetails(ctx, "P1")
+			errs <- err
+		}()
+	}
+
+	key := cacheKeyForDetails(client.getLocale(), "P1")
+	for _, ctx := range contexts {
+		waitForWaiters(t, &client.inflight, flightKey(ctx, key), 1)
+	}
+	close(release)
+	for range contexts {
+		if err := <-errs; err != nil {
+			t.Errorf("ProductDetails: %v", err)
+		}
+	}
+	if calls.Load() != int32(len(contexts)) {
+		t.Errorf("expected %d API calls, got %d", len(contexts), calls.Load())
+	}
+}
@@ -15,8 +15,8 @@
 // Search for products:
 //
 //	results, err := client.KeywordSearch(ctx, &digikey.SearchRequest{
-//	    Keywords:    "STM32F4",
-//	    RecordCount: 10,
+//	    Keywords: "STM32F4",
+//	    Limit:    10,
 //	})
 //
 // Or use the fluent search builder:
@@ -0,0 +1,312 @@
+package digikey
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskCacheTempPrefix marks files being written; they are skipped by scans.
+const diskCacheTempPrefix = ".tmp-"
+
+// diskCacheStaleTemp is how old a temporary file must be before eviction
+// assumes its writer crashed and removes it.
+const diskCacheStaleTemp = time.Hour
+
+// DiskCache is a Cache that stores entries as files under a directory, so
+// cached responses survive restarts and can be shared by processes on one
+// host. Each entry is a file named after the SHA-256 of its key, written
+// atomically by renaming a temporary file. Reads refresh the file's
+// modification time, and when the total size passes the cap the least
+// recently used entries are removed.
+//
+// The Cache interface has no error results, so I/O failures are treated as
+// cache misses.
+type DiskCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+
+	mu   sync.Mutex
+	size int64 // Estimated bytes on disk, corrected by each scan
+}
+
+// diskCacheHeader is the first line of an entry file.
+type diskCacheHeader struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// diskCacheFile describes an entry file found by a scan.
+type diskCacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// NewDiskCache creates a disk cache in dir, creating the directory if
+// needed. Entries use defaultTTL when Set is called with a zero TTL.
+// maxBytes caps the total size of entry files; 0 means no cap.
+func NewDiskCache(dir string, defaultTTL time.Duration, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	c := &DiskCache{dir: dir, ttl: defaultTTL, maxBytes: maxBytes}
+	files, err := c.scan()
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		c.size += f.size
+	}
+	return c, nil
+}
+
+// Get retrieves a value from the cache.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	header, value, ok := parseDiskCacheEntry(data)
+	if !ok || header.Key != key {
+		return nil, false
+	}
+
+	now := time.Now()
+	if now.After(header.ExpiresAt) {
+		c.remove(path, int64(len(data)))
+		return nil, false
+	}
+
+	_ = os.Chtimes(path, now, now)
+	return value, true
+}
+
+// Set stores a value in the cache with the specified TTL.
+// If ttl is 0, the default TTL is used.
+func (c *DiskCache) Set(key string, value []byte, ttl time.Duration) {
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+
+	header, err := json.Marshal(diskCacheHeader{Key: key, ExpiresAt: time.Now().Add(ttl)})
+	if err != nil {
+		return
+	}
+	data := make([]byte, 0, len(header)+1+len(value))
+	data = append(append(append(data, header...), '\n'), value...)
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+
+	tmp, err := os.CreateTemp(c.dir, diskCacheTempPrefix+"*")
+	if err != nil {
+		return
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.size += int64(len(data)) - replaced
+	over := c.maxBytes > 0 && c.size > c.maxBytes
+	c.mu.Unlock()
+
+	if over {
+		c.evict()
+	}
+}
+
+// Delete removes a value from the cache.
+func (c *DiskCache) Delete(key string) {
+	path := c.path(key)
+	if info, err := os.Stat(path); err == nil {
+		c.remove(path, info.Size())
+	}
+}
+
+// Clear removes all entries from the cache.
+func (c *DiskCache) Clear() {
+	files, err := c.scan()
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		c.remove(f.path, f.size)
+	}
+}
+
+// DeleteMatching removes the entries whose keys match and returns how many
+// were removed. Every entry file's header is read to recover its key.
+func (c *DiskCache) DeleteMatching(match func(key string) bool) int {
+	files, err := c.scan()
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, f := range files {
+		header, ok := readDiskCacheHeader(f.path)
+		if !ok || !match(header.Key) {
+			continue
+		}
+		if c.remove(f.path, f.size) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Size returns the number of entries in the cache, including expired
+// entries not yet removed.
+func (c *DiskCache) Size() int {
+	files, err := c.scan()
+	if err != nil {
+		return 0
+	}
+	return len(files)
+}
+
+// evict removes expired entries, then the least recently used ones until the
+// cache fits within maxBytes.
+func (c *DiskCache) evict() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := c.scan()
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	var total int64
+	live := files[:0]
+	for _, f := range files {
+		if header, ok := readDiskCacheHeader(f.path); ok && now.After(header.ExpiresAt) {
+			// An entry that cannot be removed still takes up space
+			if err := os.Remove(f.path); err == nil || errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+		}
+		total += f.size
+		live = append(live, f)
+	}
+
+	sort.Slice(live, func(i, j int) bool { return live[i].modTime.Before(live[j].modTime) })
+	for _, f := range live {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil || errors.Is(err, fs.ErrNotExist) {
+			total -= f.size
+		}
+	}
+	c.size = total
+}
+
+// scan lists entry files and removes abandoned temporary files.
+func (c *DiskCache) scan() ([]diskCacheFile, error) {
+	var files []diskCacheFile
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), diskCacheTempPrefix) {
+			if time.Since(info.ModTime()) > diskCacheStaleTemp {
+				_ = os.Remove(path)
+			}
+			return nil
+		}
+
+		files = append(files, diskCacheFile{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return files, err
+}
+
+// remove deletes an entry file and updates the size estimate. It reports
+// whether the file was removed.
+func (c *DiskCache) remove(path string, size int64) bool {
+	if err := os.Remove(path); err != nil {
+		return false
+	}
+	c.mu.Lock()
+	c.size -= size
+	c.mu.Unlock()
+	return true
+}
+
+// path returns the entry file for key. Files are spread over subdirectories
+// named after the first byte of the hash to keep directories small.
+func (c *DiskCache) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(hash[:])
+	return filepath.Join(c.dir, name[:2], name)
+}
+
+// parseDiskCacheEntry splits an entry file into its header and value.
+func parseDiskCacheEntry(data []byte) (diskCacheHeader, []byte, bool) {
+	var header diskCacheHeader
+	line, value, found := bytes.Cut(data, []byte{'\n'})
+	if !found || json.Unmarshal(line, &header) != nil {
+		return header, nil, false
+	}
+	return header, value, true
+}
+
+// readDiskCacheHeader reads only the header line of an entry file.
+func readDiskCacheHeader(path string) (diskCacheHeader, bool) {
+	var header diskCacheHeader
+	f, err := os.Open(path)
+	if err != nil {
+		return header, false
+	}
+	defer func() { _ = f.Close() }()
+
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil || json.Unmarshal(line, &header) != nil {
+		return header, false
+	}
+	return header, true
+}
@@ -0,0 +1,202 @@
+package digikey
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestDiskCacheImplementsCache verifies DiskCache implements Cache.
+func TestDiskCacheImplementsCache(t *testing.T) {
+	var _ Cache = (*DiskCache)(nil)
+}
+
+// TestDiskCacheSetGet tests storing, reading and deleting entries.
+func TestDiskCacheSetGet(t *testing.T) {
+	c, err := NewDiskCache(t.TempDir(), time.Minute, 0)
+	if err != nil {
+		t.Fatalf("NewDiskCache: %v", err)
+	}
+
+	if _, ok := c.Get("missing"); ok {
+		t.Error("expected miss for missing key")
+	}
+
+	c.Set("details:US:USD:P5555-ND", []byte(`{"Product":{}}`), 0)
+	value, ok := c.Get("details:US:USD:P5555-ND")
+	if !ok || string(value) != `{"Product":{}}` {
+		t.Errorf("expected stored value, got %q, %v", value, ok)
+	}
+
+	// Values may contain newlines
+	c.Set("multi", []byte("a\nb\n"), 0)
+	if value, _ := c.Get("multi"); string(value) != "a\nb\n" {
+		t.Errorf("expected value with newlines, got %q", value)
+	}
+
+	c.Delete("multi")
+	if _, ok := c.Get("multi"); ok {
+		t.Error("expected deleted entry to miss")
+	}
+	if n := c.Size(); n != 1 {
+		t.Errorf("expected 1 entry, got %d", n)
+	}
+
+	c.Clear()
+	if n := c.Size(); n != 0 {
+		t.Errorf("expected empty cache after Clear, got %d", n)
+	}
+}
+
+// TestDiskCacheExpiration tests that expired entries miss and are removed.
+func TestDiskCacheExpiration(t *testing.T) {
+	c, err := NewDiskCache(t.TempDir(), time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Set("key", []byte("value"), 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := c.Get("key"); ok {
+		t.Error("expected expired entry to miss")
+	}
+	if n := c.Size(); n != 0 {
+		t.Errorf("expected expired entry to be removed, got %d entries", n)
+	}
+}
+
+// TestDiskCachePersistence tests that entries are visible to a new instance.
+func TestDiskCachePersistence(t *testing.T) {
+	dir := t.TempDir()
+	first, _ := NewDiskCache(dir, time.Minute, 0)
+	first.Set("key", []byte("value"), 0)
+
+	second, err := NewDiskCache(dir, time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, ok := second.Get("key"); !ok || string(value) != "value" {
+		t.Errorf("expected value from previous instance, got %q, %v", value, ok)
+	}
+}
+
+// TestDiskCacheKeyMismatch tests that a file for another key is not returned.
+func TestDiskCacheKeyMismatch(t *testing.T) {
+	c, _ := NewDiskCache(t.TempDir(), time.Minute, 0)
+	c.Set("other", []byte("value"), 0)
+
+	// Put the entry for "other" where "key" would live
+	if err := os.MkdirAll(filepath.Dir(c.path("key")), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(c.path("other"), c.path("key")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.Get("key"); ok {
+		t.Error("expected entry with mismatched key to miss")
+	}
+}
+
+// TestDiskCacheEviction tests that the least recently used entries are
+// removed when the size cap is exceeded.
+func TestDiskCacheEviction(t *testing.T) {
+	c, _ := NewDiskCache(t.TempDir(), time.Minute, 0)
+	value := []byte(strings.Repeat("x", 100))
+
+	c.Set("a", value, 0)
+	c.Set("b", value, 0)
+	c.Set("c", value, 0)
+
+	// Make "a" the most recently used, then "c", leaving "b" oldest
+	base := time.Now().Add(-time.Hour)
+	for i, key := range []string{"b", "c", "a"} {
+		mod := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(c.path(key), mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Entry sizes vary by a few bytes with the expiry timestamp, so leave
+	// room for three entries but not four
+	info, _ := os.Stat(c.path("a"))
+	c.maxBytes = 3*info.Size() + info.Size()/2
+	c.Set("d", value, 0)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("expected %q to be kept", key)
+		}
+	}
+}
+
+// TestDiskCacheConcurrent tests concurrent use by several instances.
+func TestDiskCacheConcurrent(t *testing.T) {
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		c, err := NewDiskCache(dir, time.Minute, 4096)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wg.Add(1)
+		go func(i int, c *DiskCache) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				key := fmt.Sprintf("key-%d", j%10)
+				want := []byte(strings.Repeat(key, 10))
+				c.Set(key, want, 0)
+				if got, ok := c.Get(key); ok && string(got) != string(want) {
+					t.Errorf("corrupt value for %s: %q", key, got)
+				}
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	matches, _ := filepath.Glob(filepath.Join(dir, diskCacheTempPrefix+"*"))
+	if len(matches) != 0 {
+		t.Errorf("expected no leftover temporary files, got %v", matches)
+	}
+}
+
+// TestClientWithDiskCache tests serving responses from a disk cache.
+func TestClientWithDiskCache(t *testing.T) {
+	cache, err := NewDiskCache(t.TempDir(), time.Minute, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"P5555-ND"}}`))
+	}, WithCache(cache))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.ProductDetails(ctx, "P5555-ND")
+		if err != nil {
+			t.Fatalf("ProductDetails: %v", err)
+		}
+		if resp.Product.DigiKeyProductNumber != "P5555-ND" {
+			t.Errorf("unexpected response %+v", resp.Product)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 API call, got %d", calls)
+	}
+}
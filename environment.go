@@ -0,0 +1,80 @@
+package digikey
+
+import "strings"
+
+const (
+	sandboxBaseURL      = "https://sandbox-api.digikey.com"
+	sandboxTokenURL     = "https://sandbox-api.digikey.com/v1/oauth2/token"
+	sandboxAuthorizeURL = "https://sandbox-api.digikey.com/v1/oauth2/authorize"
+
+	// sandboxKeyPrefix marks cache keys for sandbox responses. Production
+	// keys have no prefix, so existing caches stay valid.
+	sandboxKeyPrefix = "sandbox|"
+)
+
+// Environment selects the Digi-Key API environment.
+type Environment int
+
+const (
+	// Production is the live API at api.digikey.com.
+	Production Environment = iota
+
+	// Sandbox is the test API at sandbox-api.digikey.com. It needs the
+	// credentials of an app registered for the sandbox.
+	Sandbox
+)
+
+// String returns the environment name.
+func (e Environment) String() string {
+	switch e {
+	case Production:
+		return "production"
+	case Sandbox:
+		return "sandbox"
+	default:
+		return "unknown"
+	}
+}
+
+// urls returns the API base, token and authorization URLs for e.
+func (e Environment) urls() (baseURL, tokenURL, authorizeURL string) {
+	if e == Sandbox {
+		return sandboxBaseURL, sandboxTokenURL, sandboxAuthorizeURL
+	}
+	return defaultBaseURL, defaultTokenURL, defaultAuthorizeURL
+}
+
+// cacheKey returns key scoped to e, so responses from different
+// environments never share cache entries.
+func (e Environment) cacheKey(key string) string {
+	if e == Sandbox {
+		return sandboxKeyPrefix + key
+	}
+	return key
+}
+
+// splitCacheKey returns the environment of a cache key and the key without
+// its environment prefix.
+func splitCacheKey(key string) (Environment, string) {
+	if rest, ok := strings.CutPrefix(key, sandboxKeyPrefix); ok {
+		return Sandbox, rest
+	}
+	return Production, key
+}
+
+// WithEnvironment selects the Digi-Key API environment, setting the base,
+// token and authorization URLs together. Options after it, such as
+// WithBaseURL, override its URLs. Cached responses are kept apart by
+// environment, so a cache shared between sandbox and production clients
+// never returns sandbox data to production.
+func WithEnvironment(env Environment) ClientOption {
+	return func(c *Client) {
+		c.environment = env
+		c.baseURL, c.tokenManager.tokenURL, c.tokenManager.authorizeURL = env.urls()
+	}
+}
+
+// Environment returns the API environment the client uses.
+func (c *Client) Environment() Environment {
+	return c.environment
+}
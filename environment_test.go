@@ -0,0 +1,96 @@
+package digikey
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestWithEnvironment tests that the environment sets all URLs together.
+func TestWithEnvironment(t *testing.T) {
+	client := NewClient("id", "secret", WithEnvironment(Sandbox))
+	if client.Environment() != Sandbox {
+		t.Errorf("expected sandbox, got %v", client.Environment())
+	}
+	if client.baseURL != sandboxBaseURL || client.tokenManager.tokenURL != sandboxTokenURL || client.tokenManager.authorizeURL != sandboxAuthorizeURL {
+		t.Errorf("unexpected URLs %s, %s, %s", client.baseURL, client.tokenManager.tokenURL, client.tokenManager.authorizeURL)
+	}
+
+	client = NewClient("id", "secret")
+	if client.Environment() != Production || client.baseURL != defaultBaseURL || client.tokenManager.tokenURL != defaultTokenURL {
+		t.Errorf("expected production URLs by default, got %s, %s", client.baseURL, client.tokenManager.tokenURL)
+	}
+}
+
+// TestWithEnvironmentOverride tests that later options override the
+// environment's URLs, and that token options apply in a single pass.
+func TestWithEnvironmentOverride(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Second}
+	client := NewClient("id", "secret",
+		WithEnvironment(Sandbox),
+		WithTokenURL("https://auth.example.com/token"),
+		WithHTTPClient(httpClient),
+	)
+	if client.baseURL != sandboxBaseURL || client.tokenManager.tokenURL != "https://auth.example.com/token" {
+		t.Errorf("unexpected URLs %s, %s", client.baseURL, client.tokenManager.tokenURL)
+	}
+	if client.tokenManager.httpClient != httpClient {
+		t.Error("token manager should use the configured HTTP client")
+	}
+}
+
+// TestEnvironmentCacheKeys tests that a shared cache keeps sandbox and
+// production responses apart.
+func TestEnvironmentCacheKeys(t *testing.T) {
+	cache := NewMemoryCache(time.Minute)
+	defer func() { _ = cache.Close() }()
+
+	handler := func(number string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"Product":{"DigiKeyProductNumber":"` + number + `"}}`))
+		}
+	}
+	production := newMockClient(t, handler("production"), WithCache(cache))
+	sandbox := newMockClient(t, handler("sandbox"), WithCache(cache))
+	sandbox.environment = Sandbox
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for _, tc := range []struct {
+		client *Client
+		want   string
+	}{{production, "production"}, {sandbox, "sandbox"}, {production, "production"}} {
+		resp, err := tc.client.ProductDetails(ctx, "P5555-ND")
+		if err != nil {
+			t.Fatalf("ProductDetails: %v", err)
+		}
+		if resp.Product.DigiKeyProductNumber != tc.want {
+			t.Errorf("%v client got %q", tc.client.Environment(), resp.Product.DigiKeyProductNumber)
+		}
+	}
+	if stats := cache.CacheStats(); stats.Entries != 2 || stats.Kinds["details"].Entries != 2 {
+		t.Errorf("expected 2 entries under the details kind, got %+v", stats)
+	}
+
+	// Invalidation is scoped to the client's environment
+	n, err := sandbox.InvalidateCache(CacheSelector{ProductNumber: "P5555-ND"})
+	if err != nil || n != 1 {
+		t.Errorf("expected 1 sandbox entry removed, got %d, %v", n, err)
+	}
+	if _, ok := cache.Get(cacheKeyForDetails(production.getLocale(), "P5555-ND")); !ok {
+		t.Error("production entry should be kept")
+	}
+
+	// So is clearing
+	cache.Set(Sandbox.cacheKey(cacheKeyForDetails(sandbox.getLocale(), "OTHER")), []byte("{}"), 0)
+	sandbox.ClearCache()
+	if stats := cache.CacheStats(); stats.Entries != 1 {
+		t.Errorf("expected only the production entry left, got %+v", stats)
+	}
+	production.ClearCache()
+	if stats := cache.CacheStats(); stats.Entries != 0 {
+		t.Errorf("expected an empty cache, got %+v", stats)
+	}
+}
@@ -3,12 +3,18 @@ package digikey
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
 	// ErrUnauthorized indicates invalid or expired credentials.
 	ErrUnauthorized = errors.New("digikey: unauthorized")
 
+	// ErrAuthorizationRequired indicates that the authorization code flow
+	// has no tokens yet, or its refresh token has expired, so the user must
+	// log in again.
+	ErrAuthorizationRequired = errors.New("digikey: authorization required")
+
 	// ErrForbidden indicates the request was forbidden.
 	ErrForbidden = errors.New("digikey: forbidden")
 
@@ -23,6 +29,10 @@ var (
 
 	// ErrServerError indicates a server-side error.
 	ErrServerError = errors.New("digikey: server error")
+
+	// ErrCacheUnsupported indicates the cache does not support an operation,
+	// such as selective invalidation.
+	ErrCacheUnsupported = errors.New("digikey: operation not supported by cache")
 )
 
 // APIError represents an error returned by the Digi-Key API.
@@ -79,6 +89,54 @@ func (e *AuthError) Unwrap() error {
 	return ErrUnauthorized
 }
 
+// TokenError represents a failed request to the OAuth2 token endpoint.
+// Rejected credentials or grants match ErrUnauthorized, and also *AuthError
+// when the response carries an OAuth2 error code. Rate limiting and server
+// errors match ErrRateLimitExceeded and ErrServerError.
+type TokenError struct {
+	StatusCode  int
+	Code        string // OAuth2 error code, such as "invalid_client"
+	Description string // OAuth2 error description
+	Details     string // Response body, when it is not an OAuth2 error
+	RequestID   string
+}
+
+func (e *TokenError) Error() string {
+	msg := fmt.Sprintf("digikey: token request failed (status %d)", e.StatusCode)
+	switch {
+	case e.Code != "" && e.Description != "":
+		msg += fmt.Sprintf(": %s: %s", e.Code, e.Description)
+	case e.Code != "":
+		msg += ": " + e.Code
+	case e.Details != "":
+		msg += ": " + e.Details
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error type for errors.Is and errors.As matching.
+func (e *TokenError) Unwrap() error {
+	switch {
+	case e.StatusCode == 429:
+		return ErrRateLimitExceeded
+	case e.StatusCode >= 500:
+		return ErrServerError
+	case e.Code != "":
+		return &AuthError{Err: e.Code, Description: e.Description}
+	default:
+		return ErrUnauthorized
+	}
+}
+
+// Retryable reports whether the failure is on Digi-Key's side, such as rate
+// limiting or an outage, rather than a problem with the credentials or grant.
+func (e *TokenError) Retryable() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
+
 // RateLimitError provides details about rate limit violations.
 type RateLimitError struct {
 	Limit     int
@@ -96,3 +154,23 @@ func (e *RateLimitError) Error() string {
 func (e *RateLimitError) Unwrap() error {
 	return ErrRateLimitExceeded
 }
+
+// ResolveError indicates that a name could not be resolved to a Digi-Key ID.
+type ResolveError struct {
+	Kind       string   // "manufacturer", "parameter", etc.
+	Name       string   // The name that was looked up
+	Candidates []string // Close or ambiguous matches, if any
+}
+
+func (e *ResolveError) Error() string {
+	if len(e.Candidates) > 0 {
+		return fmt.Sprintf("digikey: cannot resolve %s %q (candidates: %s)",
+			e.Kind, e.Name, strings.Join(e.Candidates, ", "))
+	}
+	return fmt.Sprintf("digikey: unknown %s %q", e.Kind, e.Name)
+}
+
+// Unwrap returns the underlying invalid request error.
+func (e *ResolveError) Unwrap() error {
+	return ErrInvalidRequest
+}
@@ -183,3 +183,20 @@ func contains(s, substring string) bool {
 	}
 	return false
 }
+
+// TestResolveError tests ResolveError formatting and unwrapping.
+func TestResolveError(t *testing.T) {
+	err := &ResolveError{Kind: "manufacturer", Name: "Foo"}
+	if err.Error() != `digikey: unknown manufacturer "Foo"` {
+		t.Errorf("unexpected error string: %s", err.Error())
+	}
+
+	err.Candidates = []string{"Foobar", "Foo Inc"}
+	if !contains(err.Error(), "Foobar, Foo Inc") {
+		t.Errorf("expected candidates in error, got %s", err.Error())
+	}
+
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Error("expected ResolveError to unwrap to ErrInvalidRequest")
+	}
+}
@@ -0,0 +1,323 @@
+package digikey
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// SlidingWindowLimiter enforces Digi-Key's limits over rolling windows by
+// remembering when each request was made, so no 60-second span ever holds
+// more than the minute limit.
+type SlidingWindowLimiter struct {
+	mu sync.Mutex
+
+	minute       slidingLog
+	day          slidingLog
+	blockedUntil time.Time
+
+	queue waitQueue
+}
+
+// NewSlidingWindowLimiter creates a sliding window limiter with custom limits.
+func NewSlidingWindowLimiter(minuteLimit, dayLimit int) *SlidingWindowLimiter {
+	return &SlidingWindowLimiter{
+		minute: slidingLog{window: time.Minute, limit: minuteLimit},
+		day:    slidingLog{window: 24 * time.Hour, limit: dayLimit},
+	}
+}
+
+// Allow checks if a request is allowed and records it if so.
+func (l *SlidingWindowLimiter) Allow() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.minute.prune(now)
+	l.day.prune(now)
+
+	if now.Before(l.blockedUntil) {
+		return newRateLimitError(l.minute.limit, l.blockedUntil, "minute")
+	}
+	if l.minute.full() {
+		return newRateLimitError(l.minute.limit, l.minute.nextFree(now), "minute")
+	}
+	if l.day.full() {
+		return newRateLimitError(l.day.limit, l.day.nextFree(now), "day")
+	}
+
+	l.minute.add(now)
+	l.day.add(now)
+	return nil
+}
+
+// Wait blocks until a request is allowed, then records it like Allow.
+// Concurrent callers are admitted in the order they called Wait.
+func (l *SlidingWindowLimiter) Wait(ctx context.Context) error {
+	return l.queue.wait(ctx, l.Allow, l.WaitTime)
+}
+
+// WaitTime returns how long to wait before the next request is allowed.
+func (l *SlidingWindowLimiter) WaitTime() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.minute.prune(now)
+	l.day.prune(now)
+
+	var wait time.Duration
+	if now.Before(l.blockedUntil) {
+		wait = l.blockedUntil.Sub(now)
+	}
+	if l.minute.full() {
+		wait = max(wait, l.minute.nextFree(now).Sub(now))
+	}
+	if l.day.full() {
+		wait = max(wait, l.day.nextFree(now).Sub(now))
+	}
+	return wait
+}
+
+// Stats returns current rate limit statistics. The reset times are when the
+// oldest request in each window expires and frees a slot.
+func (l *SlidingWindowLimiter) Stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.minute.prune(now)
+	l.day.prune(now)
+
+	minuteUsed := len(l.minute.times)
+	minuteReset := l.minute.nextFree(now)
+	if now.Before(l.blockedUntil) {
+		minuteUsed = l.minute.limit
+		minuteReset = l.blockedUntil
+	}
+
+	return RateLimitStats{
+		MinuteLimit:     l.minute.limit,
+		MinuteUsed:      minuteUsed,
+		MinuteRemaining: l.minute.limit - minuteUsed,
+		MinuteResetAt:   minuteReset,
+		DayLimit:        l.day.limit,
+		DayUsed:         len(l.day.times),
+		DayRemaining:    l.day.limit - len(l.day.times),
+		DayResetAt:      l.day.nextFree(now),
+	}
+}
+
+// UpdateFromResponse blocks requests for the Retry-After period of a 429 response.
+func (l *SlidingWindowLimiter) UpdateFromResponse(retryAfterSeconds int) {
+	if retryAfterSeconds <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.blockedUntil = time.Now().Add(time.Duration(retryAfterSeconds) * time.Second)
+}
+
+// TokenBucketLimiter smooths requests out over time. Tokens refill
+// continuously at minuteLimit per minute into a bucket holding at most burst
+// tokens, and each request takes one. The day limit is enforced over a
+// rolling 24 hours.
+//
+// Any 60-second span admits at most burst+minuteLimit requests, so choose
+// values whose sum stays within Digi-Key's limit, such as
+// NewTokenBucketLimiter(100, 1000, 20).
+type TokenBucketLimiter struct {
+	mu sync.Mutex
+
+	rate         float64 // tokens per second
+	burst        int
+	tokens       float64
+	last         time.Time
+	day          slidingLog
+	blockedUntil time.Time
+
+	queue waitQueue
+}
+
+// NewTokenBucketLimiter creates a token bucket limiter that starts full.
+func NewTokenBucketLimiter(minuteLimit, dayLimit, burst int) *TokenBucketLimiter {
+	if minuteLimit < 1 {
+		minuteLimit = 1
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucketLimiter{
+		rate:   float64(minuteLimit) / time.Minute.Seconds(),
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+		day:    slidingLog{window: 24 * time.Hour, limit: dayLimit},
+	}
+}
+
+// Allow takes a token if one is available.
+func (l *TokenBucketLimiter) Allow() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+	l.day.prune(now)
+
+	if now.Before(l.blockedUntil) {
+		return newRateLimitError(l.burst, l.blockedUntil, "minute")
+	}
+	if l.tokens < 1 {
+		return newRateLimitError(l.burst, now.Add(l.untilTokens(1)), "minute")
+	}
+	if l.day.full() {
+		return newRateLimitError(l.day.limit, l.day.nextFree(now), "day")
+	}
+
+	l.tokens--
+	l.day.add(now)
+	return nil
+}
+
+// Wait blocks until a token is available, then takes it like Allow.
+// Concurrent callers are admitted in the order they called Wait.
+func (l *TokenBucketLimiter) Wait(ctx context.Context) error {
+	return l.queue.wait(ctx, l.Allow, l.WaitTime)
+}
+
+// WaitTime returns how long to wait before the next token is available.
+func (l *TokenBucketLimiter) WaitTime() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+	l.day.prune(now)
+
+	wait := l.untilTokens(1)
+	if now.Before(l.blockedUntil) {
+		wait += l.blockedUntil.Sub(now)
+	}
+	if l.day.full() {
+		wait = max(wait, l.day.nextFree(now).Sub(now))
+	}
+	return wait
+}
+
+// Stats returns current rate limit statistics. The minute fields describe
+// the bucket: MinuteLimit is the burst size, MinuteRemaining the whole tokens
+// available, and MinuteResetAt when the bucket will be full again.
+func (l *TokenBucketLimiter) Stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.refill(now)
+	l.day.prune(now)
+
+	remaining := int(l.tokens)
+	minuteReset := now.Add(l.untilTokens(float64(l.burst)))
+	if now.Before(l.blockedUntil) {
+		remaining = 0
+		minuteReset = l.blockedUntil.Add(l.untilTokens(float64(l.burst)))
+	}
+
+	return RateLimitStats{
+		MinuteLimit:     l.burst,
+		MinuteUsed:      l.burst - remaining,
+		MinuteRemaining: remaining,
+		MinuteResetAt:   minuteReset,
+		DayLimit:        l.day.limit,
+		DayUsed:         len(l.day.times),
+		DayRemaining:    l.day.limit - len(l.day.times),
+		DayResetAt:      l.day.nextFree(now),
+	}
+}
+
+// UpdateFromResponse empties the bucket and blocks requests for the
+// Retry-After period of a 429 response.
+func (l *TokenBucketLimiter) UpdateFromResponse(retryAfterSeconds int) {
+	if retryAfterSeconds <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = 0
+	l.last = now
+	l.blockedUntil = now.Add(time.Duration(retryAfterSeconds) * time.Second)
+}
+
+// refill adds the tokens accrued since the last refill. No tokens accrue
+// while requests are blocked after a 429 response.
+func (l *TokenBucketLimiter) refill(now time.Time) {
+	start := l.last
+	if start.Before(l.blockedUntil) {
+		start = l.blockedUntil
+	}
+	if now.After(start) {
+		l.tokens = math.Min(float64(l.burst), l.tokens+now.Sub(start).Seconds()*l.rate)
+	}
+	l.last = now
+}
+
+// untilTokens returns how long until the bucket holds n tokens.
+func (l *TokenBucketLimiter) untilTokens(n float64) time.Duration {
+	if l.tokens >= n {
+		return 0
+	}
+	return time.Duration((n - l.tokens) / l.rate * float64(time.Second))
+}
+
+// slidingLog records request times within a rolling window.
+type slidingLog struct {
+	window time.Duration
+	limit  int
+	times  []time.Time
+}
+
+// prune drops requests that have left the window.
+func (s *slidingLog) prune(now time.Time) {
+	expired := 0
+	for expired < len(s.times) && !now.Before(s.times[expired].Add(s.window)) {
+		expired++
+	}
+	if expired > 0 {
+		s.times = append(s.times[:0], s.times[expired:]...)
+	}
+}
+
+// full reports whether the window holds limit requests.
+func (s *slidingLog) full() bool {
+	return len(s.times) >= s.limit
+}
+
+// add records a request.
+func (s *slidingLog) add(now time.Time) {
+	s.times = append(s.times, now)
+}
+
+// nextFree returns when the oldest request leaves the window, or now if
+// the window is empty.
+func (s *slidingLog) nextFree(now time.Time) time.Time {
+	if len(s.times) == 0 {
+		return now
+	}
+	return s.times[0].Add(s.window)
+}
+
+// newRateLimitError creates a RateLimitError for an exhausted limit.
+func newRateLimitError(limit int, resetAt time.Time, kind string) *RateLimitError {
+	return &RateLimitError{
+		Limit:     limit,
+		Remaining: 0,
+		ResetAt:   resetAt.Format(time.RFC3339),
+		Type:      kind,
+	}
+}
@@ -0,0 +1,184 @@
+package digikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestLimiterInterface tests that all limiters implement Limiter.
+func TestLimiterInterface(t *testing.T) {
+	var _ Limiter = (*RateLimiter)(nil)
+	var _ Limiter = (*SlidingWindowLimiter)(nil)
+	var _ Limiter = (*TokenBucketLimiter)(nil)
+}
+
+// TestSlidingWindowLimiterAllow tests that the minute limit holds across
+// what would be a fixed window boundary.
+func TestSlidingWindowLimiterAllow(t *testing.T) {
+	l := NewSlidingWindowLimiter(2, 1000)
+
+	if err := l.Allow(); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if err := l.Allow(); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+
+	// Pretend both requests were made 30 seconds ago
+	l.mu.Lock()
+	for i := range l.minute.times {
+		l.minute.times[i] = l.minute.times[i].Add(-30 * time.Second)
+	}
+	l.mu.Unlock()
+
+	err := l.Allow()
+	var rle *RateLimitError
+	if !errors.As(err, &rle) || rle.Type != "minute" {
+		t.Fatalf("expected minute RateLimitError, got %v", err)
+	}
+
+	wait := l.WaitTime()
+	if wait < 29*time.Second || wait > 30*time.Second {
+		t.Errorf("expected ~30s wait, got %v", wait)
+	}
+
+	stats := l.Stats()
+	if stats.MinuteUsed != 2 || stats.MinuteRemaining != 0 || stats.DayUsed != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// TestSlidingWindowLimiterExpiry tests that requests leave the window.
+func TestSlidingWindowLimiterExpiry(t *testing.T) {
+	l := NewSlidingWindowLimiter(1, 1000)
+	_ = l.Allow()
+
+	l.mu.Lock()
+	l.minute.times[0] = time.Now().Add(-time.Minute)
+	l.mu.Unlock()
+
+	if wait := l.WaitTime(); wait != 0 {
+		t.Errorf("expected no wait, got %v", wait)
+	}
+	if err := l.Allow(); err != nil {
+		t.Errorf("expected request to be allowed after the window, got %v", err)
+	}
+}
+
+// TestSlidingWindowLimiterDayLimit tests the rolling day limit.
+func TestSlidingWindowLimiterDayLimit(t *testing.T) {
+	l := NewSlidingWindowLimiter(100, 2)
+	_ = l.Allow()
+	_ = l.Allow()
+
+	err := l.Allow()
+	var rle *RateLimitError
+	if !errors.As(err, &rle) || rle.Type != "day" {
+		t.Fatalf("expected day RateLimitError, got %v", err)
+	}
+	if wait := l.WaitTime(); wait < 23*time.Hour {
+		t.Errorf("expected wait close to a day, got %v", wait)
+	}
+}
+
+// TestSlidingWindowLimiterUpdateFromResponse tests blocking after a 429.
+func TestSlidingWindowLimiterUpdateFromResponse(t *testing.T) {
+	l := NewSlidingWindowLimiter(100, 1000)
+	l.UpdateFromResponse(30)
+
+	if err := l.Allow(); err == nil {
+		t.Error("expected requests to be blocked after Retry-After")
+	}
+	if wait := l.WaitTime(); wait < 29*time.Second {
+		t.Errorf("expected ~30s wait, got %v", wait)
+	}
+	if stats := l.Stats(); stats.MinuteRemaining != 0 {
+		t.Errorf("expected no remaining requests, got %d", stats.MinuteRemaining)
+	}
+}
+
+// TestTokenBucketLimiterAllow tests burst capacity and refill.
+func TestTokenBucketLimiterAllow(t *testing.T) {
+	// 6000/minute refills a token every 10ms
+	l := NewTokenBucketLimiter(6000, 1000, 2)
+
+	if err := l.Allow(); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	if err := l.Allow(); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	if err := l.Allow(); err == nil {
+		t.Fatal("expected empty bucket to reject request")
+	}
+
+	wait := l.WaitTime()
+	if wait <= 0 || wait > 10*time.Millisecond {
+		t.Errorf("expected wait up to 10ms, got %v", wait)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if err := l.Allow(); err != nil {
+		t.Errorf("expected refilled token, got %v", err)
+	}
+
+	stats := l.Stats()
+	if stats.MinuteLimit != 2 || stats.DayUsed != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+// TestTokenBucketLimiterWait tests that Wait takes tokens as they refill.
+func TestTokenBucketLimiterWait(t *testing.T) {
+	l := NewTokenBucketLimiter(6000, 1000, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait %d: %v", i, err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("expected requests to be spaced out, took %v", elapsed)
+	}
+}
+
+// TestTokenBucketLimiterUpdateFromResponse tests blocking after a 429.
+func TestTokenBucketLimiterUpdateFromResponse(t *testing.T) {
+	l := NewTokenBucketLimiter(6000, 1000, 10)
+	l.UpdateFromResponse(30)
+
+	if err := l.Allow(); err == nil {
+		t.Error("expected requests to be blocked after Retry-After")
+	}
+	if wait := l.WaitTime(); wait < 29*time.Second {
+		t.Errorf("expected ~30s wait, got %v", wait)
+	}
+}
+
+// TestClientWithSlidingWindowLimiter tests using an alternative limiter.
+func TestClientWithSlidingWindowLimiter(t *testing.T) {
+	limiter := NewSlidingWindowLimiter(1, 1000)
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Product":{}}`))
+	}, WithoutCache(), WithRateLimiter(limiter))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.ProductDetails(ctx, "A"); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	if _, err := client.ProductDetails(ctx, "B"); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("expected ErrRateLimitExceeded, got %v", err)
+	}
+	if stats := client.RateLimitStats(); stats.MinuteUsed != 1 {
+		t.Errorf("expected client stats from custom limiter, got %+v", stats)
+	}
+}
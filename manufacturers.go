@@ -0,0 +1,260 @@
+package digikey
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// defaultManufacturerAliases maps common abbreviations to Digi-Key manufacturer names.
+var defaultManufacturerAliases = map[string]string{
+	"TI":         "Texas Instruments",
+	"ST":         "STMicroelectronics",
+	"STM":        "STMicroelectronics",
+	"STMicro":    "STMicroelectronics",
+	"ADI":        "Analog Devices Inc.",
+	"Analog":     "Analog Devices Inc.",
+	"NXP":        "NXP USA Inc.",
+	"Microchip":  "Microchip Technology",
+	"ON Semi":    "onsemi",
+	"ON":         "onsemi",
+	"Infineon":   "Infineon Technologies",
+	"Renesas":    "Renesas Electronics America Inc",
+	"TE":         "TE Connectivity AMP Connectors",
+	"Murata":     "Murata Electronics",
+	"Samsung":    "Samsung Electro-Mechanics",
+	"Yageo":      "YAGEO",
+	"Bourns":     "Bourns Inc.",
+	"Vishay":     "Vishay Dale",
+	"Littelfuse": "Littelfuse Inc.",
+}
+
+// corporateSuffixes are dropped when normalizing manufacturer names.
+var corporateSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "corp": true, "corporation": true,
+	"co": true, "company": true, "ltd": true, "limited": true, "llc": true,
+	"gmbh": true, "ag": true, "sa": true, "plc": true, "bv": true,
+}
+
+// ManufacturerIndex resolves manufacturer names, aliases and near matches to IDs.
+type ManufacturerIndex struct {
+	manufacturers []Manufacturer
+	byID          map[int]Manufacturer
+	byName        map[string]Manufacturer
+	aliases       map[string]string
+}
+
+// NewManufacturerIndex builds an index over the given manufacturers,
+// including the default aliases for common abbreviations such as "TI".
+func NewManufacturerIndex(manufacturers []Manufacturer) *ManufacturerIndex {
+	idx := &ManufacturerIndex{
+		manufacturers: manufacturers,
+		byID:          make(map[int]Manufacturer, len(manufacturers)),
+		byName:        make(map[string]Manufacturer, len(manufacturers)),
+		aliases:       make(map[string]string),
+	}
+
+	for _, m := range manufacturers {
+		idx.byID[m.ID] = m
+		if key := normalizeManufacturerName(m.Name); key != "" {
+			if _, exists := idx.byName[key]; !exists {
+				idx.byName[key] = m
+			}
+		}
+	}
+
+	for alias, name := range defaultManufacturerAliases {
+		idx.AddAlias(alias, name)
+	}
+
+	return idx
+}
+
+// AddAlias registers an alternative name for a manufacturer. name must match
+// a manufacturer in the index; aliases for unknown manufacturers are ignored.
+func (idx *ManufacturerIndex) AddAlias(alias, name string) {
+	target := normalizeManufacturerName(name)
+	if _, ok := idx.byName[target]; !ok {
+		return
+	}
+	idx.aliases[normalizeManufacturerName(alias)] = target
+}
+
+// Len returns the number of manufacturers in the index.
+func (idx *ManufacturerIndex) Len() int {
+	return len(idx.manufacturers)
+}
+
+// Lookup returns the manufacturer with the given ID.
+func (idx *ManufacturerIndex) Lookup(id int) (Manufacturer, bool) {
+	m, ok := idx.byID[id]
+	return m, ok
+}
+
+// Resolve returns the manufacturer matching name. Names are compared without
+// case, punctuation or corporate suffixes, then checked against aliases,
+// unique prefixes and finally close spellings. A *ResolveError is returned
+// if there is no match or the match is ambiguous.
+func (idx *ManufacturerIndex) Resolve(name string) (Manufacturer, error) {
+	key := normalizeManufacturerName(name)
+	if key == "" {
+		return Manufacturer{}, &ResolveError{Kind: "manufacturer", Name: name}
+	}
+
+	if m, ok := idx.byName[key]; ok {
+		return m, nil
+	}
+	if target, ok := idx.aliases[key]; ok {
+		return idx.byName[target], nil
+	}
+
+	var prefixed []Manufacturer
+	for normalized, m := range idx.byName {
+		if strings.HasPrefix(normalized, key+" ") {
+			prefixed = append(prefixed, m)
+		}
+	}
+	if len(prefixed) == 1 {
+		return prefixed[0], nil
+	}
+	if len(prefixed) > 1 {
+		return Manufacturer{}, &ResolveError{Kind: "manufacturer", Name: name, Candidates: manufacturerNames(prefixed, 5)}
+	}
+
+	matches := idx.Search(name, 5)
+	if len(matches) > 0 {
+		best := editDistance(key, normalizeManufacturerName(matches[0].Name))
+		if best <= maxEditDistance(key) {
+			tied := len(matches) > 1 && editDistance(key, normalizeManufacturerName(matches[1].Name)) == best
+			if !tied {
+				return matches[0], nil
+			}
+		}
+	}
+
+	return Manufacturer{}, &ResolveError{Kind: "manufacturer", Name: name, Candidates: manufacturerNames(matches, 5)}
+}
+
+// Search returns up to limit manufacturers ranked by similarity to query.
+// Only manufacturers within a small edit distance or containing the query are returned.
+func (idx *ManufacturerIndex) Search(query string, limit int) []Manufacturer {
+	key := normalizeManufacturerName(query)
+	if key == "" || limit <= 0 {
+		return nil
+	}
+
+	type scored struct {
+		m     Manufacturer
+		score int
+	}
+	var results []scored
+	threshold := maxEditDistance(key)
+	for normalized, m := range idx.byName {
+		score := editDistance(key, normalized)
+		if score > threshold {
+			if !strings.Contains(normalized, key) {
+				continue
+			}
+			// Rank substring matches after close spellings
+			score = threshold + len(normalized) - len(key)
+		}
+		results = append(results, scored{m: m, score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score < results[j].score
+		}
+		return results[i].m.Name < results[j].m.Name
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	found := make([]Manufacturer, len(results))
+	for i, r := range results {
+		found[i] = r.m
+	}
+	return found
+}
+
+// ManufacturerIndex returns an index over all Digi-Key manufacturers. When
+// caching is enabled the index is reused for the details TTL.
+func (c *Client) ManufacturerIndex(ctx context.Context) (*ManufacturerIndex, error) {
+	c.manufacturerMu.Lock()
+	defer c.manufacturerMu.Unlock()
+
+	if c.manufacturerIndex != nil && time.Now().Before(c.manufacturerIndexExpiry) {
+		return c.manufacturerIndex, nil
+	}
+
+	resp, err := c.Manufacturers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := NewManufacturerIndex(resp.Manufacturers)
+	if c.cacheConfig.Enabled {
+		c.manufacturerIndex = idx
+		c.manufacturerIndexExpiry = time.Now().Add(c.cacheConfig.DetailsTTL)
+	}
+	return idx, nil
+}
+
+// normalizeManufacturerName lowercases name, strips punctuation and drops
+// trailing corporate suffixes such as "Inc." or "GmbH".
+func normalizeManufacturerName(name string) string {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&'
+	})
+	for len(fields) > 1 && corporateSuffixes[fields[len(fields)-1]] {
+		fields = fields[:len(fields)-1]
+	}
+	return strings.Join(fields, " ")
+}
+
+// maxEditDistance returns the largest edit distance accepted as a fuzzy match for key.
+func maxEditDistance(key string) int {
+	if n := len(key) / 5; n > 1 {
+		return n
+	}
+	return 1
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func manufacturerNames(manufacturers []Manufacturer, limit int) []string {
+	if len(manufacturers) > limit {
+		manufacturers = manufacturers[:limit]
+	}
+	names := make([]string, len(manufacturers))
+	for i, m := range manufacturers {
+		names[i] = m.Name
+	}
+	sort.Strings(names)
+	return names
+}
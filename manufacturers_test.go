@@ -0,0 +1,194 @@
+package digikey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func testManufacturers() []Manufacturer {
+	return []Manufacturer{
+		{ID: 296, Name: "Texas Instruments"},
+		{ID: 497, Name: "STMicroelectronics"},
+		{ID: 505, Name: "Analog Devices Inc."},
+		{ID: 1727, Name: "Analog Devices Inc./Maxim Integrated"},
+		{ID: 150, Name: "Microchip Technology"},
+		{ID: 488, Name: "onsemi"},
+	}
+}
+
+// TestManufacturerIndexResolve tests exact, alias, prefix and fuzzy resolution.
+func TestManufacturerIndexResolve(t *testing.T) {
+	idx := NewManufacturerIndex(testManufacturers())
+
+	tests := []struct {
+		name     string
+		expected int
+	}{
+		{"Texas Instruments", 296},
+		{"texas instruments", 296},
+		{"Texas Instruments Incorporated", 296},
+		{"TI", 296},
+		{"STMicro", 497},
+		{"stmicroelectronics", 497},
+		{"ON Semi", 488},
+		{"Microchip", 150},
+		{"Texas Instrumnets", 296}, // transposition
+		{"Analog Devices", 505},    // suffix dropped
+		{"Texas", 296},             // unique prefix
+	}
+
+	for _, test := range tests {
+		m, err := idx.Resolve(test.name)
+		if err != nil {
+			t.Errorf("Resolve(%q): unexpected error: %v", test.name, err)
+			continue
+		}
+		if m.ID != test.expected {
+			t.Errorf("Resolve(%q): expected ID %d, got %d (%s)", test.name, test.expected, m.ID, m.Name)
+		}
+	}
+}
+
+// TestManufacturerIndexResolveErrors tests unknown and ambiguous names.
+func TestManufacturerIndexResolveErrors(t *testing.T) {
+	idx := NewManufacturerIndex(testManufacturers())
+
+	_, err := idx.Resolve("Acme Widgets")
+	var resolveErr *ResolveError
+	if !errors.As(err, &resolveErr) {
+		t.Fatalf("expected ResolveError, got %v", err)
+	}
+	if resolveErr.Kind != "manufacturer" {
+		t.Errorf("expected kind manufacturer, got %s", resolveErr.Kind)
+	}
+
+	if _, err := idx.Resolve(""); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for empty name, got %v", err)
+	}
+
+	idx = NewManufacturerIndex([]Manufacturer{
+		{ID: 1, Name: "Panasonic Electronic Components"},
+		{ID: 2, Name: "Panasonic Industrial Devices"},
+	})
+	_, err = idx.Resolve("Panasonic")
+	if !errors.As(err, &resolveErr) {
+		t.Fatalf("expected ResolveError for ambiguous prefix, got %v", err)
+	}
+	if len(resolveErr.Candidates) != 2 {
+		t.Errorf("expected 2 candidates, got %v", resolveErr.Candidates)
+	}
+}
+
+// TestManufacturerIndexAddAlias tests custom aliases.
+func TestManufacturerIndexAddAlias(t *testing.T) {
+	idx := NewManufacturerIndex(testManufacturers())
+	idx.AddAlias("Maxim", "Analog Devices Inc./Maxim Integrated")
+	idx.AddAlias("Nowhere", "Unknown Manufacturer")
+
+	m, err := idx.Resolve("maxim")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if m.ID != 1727 {
+		t.Errorf("expected ID 1727, got %d", m.ID)
+	}
+
+	if _, err := idx.Resolve("Nowhere"); err == nil {
+		t.Error("expected alias to unknown manufacturer to be ignored")
+	}
+}
+
+// TestManufacturerIndexSearch tests ranked fuzzy search.
+func TestManufacturerIndexSearch(t *testing.T) {
+	idx := NewManufacturerIndex(testManufacturers())
+
+	results := idx.Search("analog", 10)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].ID != 505 {
+		t.Errorf("expected closest match first, got %s", results[0].Name)
+	}
+
+	if results := idx.Search("analog", 1); len(results) != 1 {
+		t.Errorf("expected limit to apply, got %d", len(results))
+	}
+
+	if m, ok := idx.Lookup(488); !ok || m.Name != "onsemi" {
+		t.Errorf("expected Lookup(488) to return onsemi, got %v", m)
+	}
+}
+
+// TestClientManufacturerIndexCached tests that the index is fetched once.
+func TestClientManufacturerIndexCached(t *testing.T) {
+	calls := 0
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_ = json.NewEncoder(w).Encode(ManufacturersResponse{Manufacturers: testManufacturers()})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	idx1, err := client.ManufacturerIndex(ctx)
+	if err != nil {
+		t.Fatalf("ManufacturerIndex failed: %v", err)
+	}
+	idx2, err := client.ManufacturerIndex(ctx)
+	if err != nil {
+		t.Fatalf("ManufacturerIndex failed: %v", err)
+	}
+
+	if idx1 != idx2 {
+		t.Error("expected cached index to be reused")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 API call, got %d", calls)
+	}
+}
+
+// TestSearchFilterByManufacturerName tests name resolution during Execute.
+func TestSearchFilterByManufacturerName(t *testing.T) {
+	var searched SearchRequest
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/products/v4/search/manufacturers":
+			_ = json.NewEncoder(w).Encode(ManufacturersResponse{Manufacturers: testManufacturers()})
+		case "/products/v4/search/keyword":
+			_ = json.NewDecoder(r.Body).Decode(&searched)
+			_, _ = w.Write([]byte(`{"Products":[]}`))
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := &FilterRequest{ManufacturerFilter: []int{150}}
+	_, err := NewSearch("op amp").
+		WithFilterOptions(filter).
+		FilterByManufacturerName("TI", "STMicro").
+		Execute(ctx, client)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if searched.FilterOptionsRequest == nil {
+		t.Fatal("expected filter options in request")
+	}
+	ids := searched.FilterOptionsRequest.ManufacturerFilter
+	if len(ids) != 3 || ids[0] != 150 || ids[1] != 296 || ids[2] != 497 {
+		t.Errorf("expected manufacturer IDs [150 296 497], got %v", ids)
+	}
+	if len(filter.ManufacturerFilter) != 1 {
+		t.Error("caller's filter should not be modified")
+	}
+
+	_, err = NewSearch("op amp").FilterByManufacturerName("Acme Widgets").Execute(ctx, client)
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for unknown manufacturer, got %v", err)
+	}
+}
@@ -187,6 +187,7 @@ type SearchRequest struct {
 	Limit                int            `json:"Limit,omitempty"`
 	Offset               int            `json:"Offset,omitempty"`
 	FilterOptionsRequest *FilterRequest `json:"FilterOptionsRequest,omitempty"`
+	SortOptions          *SortOptions   `json:"SortOptions,omitempty"`
 }
 
 // Filters represents search filters.
@@ -207,18 +208,59 @@ type ParametricFilter struct {
 
 // SortOptions represents sorting options.
 type SortOptions struct {
-	Field     string `json:"SortOption"`
-	Direction string `json:"Direction"`
-}
+	Field     SortField     `json:"Field"`
+	Direction SortDirection `json:"SortOrder"`
+}
+
+// SortField identifies the field search results are sorted by.
+type SortField string
+
+// Sort fields supported by keyword search.
+const (
+	SortByNone                             SortField = "None"
+	SortByPackaging                        SortField = "Packaging"
+	SortByProductStatus                    SortField = "ProductStatus"
+	SortByDigiKeyProductNumber             SortField = "DigiKeyProductNumber"
+	SortByManufacturerProductNumber        SortField = "ManufacturerProductNumber"
+	SortByManufacturer                     SortField = "Manufacturer"
+	SortByMinimumQuantity                  SortField = "MinimumQuantity"
+	SortByQuantityAvailable                SortField = "QuantityAvailable"
+	SortByPrice                            SortField = "Price"
+	SortBySupplier                         SortField = "Supplier"
+	SortByPriceManufacturerStandardPackage SortField = "PriceManufacturerStandardPackage"
+)
+
+// SortDirection is the order in which search results are sorted.
+type SortDirection string
+
+// Sort directions.
+const (
+	SortAscending  SortDirection = "Ascending"
+	SortDescending SortDirection = "Descending"
+)
 
 // FilterRequest represents a filter options request.
 type FilterRequest struct {
-	CategoryFilter         []int                    `json:"CategoryFilter,omitempty"`
-	ManufacturerFilter     []int                    `json:"ManufacturerFilter,omitempty"`
-	StatusFilter           []int                    `json:"StatusFilter,omitempty"`
-	PackageTypeFilter      []int                    `json:"PackageTypeFilter,omitempty"`
-	ParameterFilterRequest []ParameterFilterRequest `json:"ParameterFilterRequest,omitempty"`
-}
+	CategoryFilter           []int                    `json:"CategoryFilter,omitempty"`
+	ManufacturerFilter       []int                    `json:"ManufacturerFilter,omitempty"`
+	StatusFilter             []int                    `json:"StatusFilter,omitempty"`
+	PackageTypeFilter        []int                    `json:"PackageTypeFilter,omitempty"`
+	ParameterFilterRequest   []ParameterFilterRequest `json:"ParameterFilterRequest,omitempty"`
+	SearchOptions            []string                 `json:"SearchOptions,omitempty"`
+	MinimumQuantityAvailable int                      `json:"MinimumQuantityAvailable,omitempty"`
+}
+
+// Search options for FilterRequest.SearchOptions.
+const (
+	SearchOptionInStock          = "InStock"
+	SearchOptionNormallyStocking = "NormallyStocking"
+	SearchOptionNewProduct       = "NewProduct"
+	SearchOptionRoHSCompliant    = "RohsCompliant"
+	SearchOptionHasDatasheet     = "HasDatasheet"
+	SearchOptionHasProductPhoto  = "HasProductPhoto"
+	SearchOptionHas3DModel       = "Has3DModel"
+	SearchOptionHasCADModel      = "HasCadModel"
+)
 
 // ParameterFilterRequest represents a parameter filter request.
 type ParameterFilterRequest struct {
@@ -235,6 +277,8 @@ type SearchResponse struct {
 	FilterOptions            FilterOptions   `json:"FilterOptions"`
 	SearchLocaleUsed         SearchLocale    `json:"SearchLocaleUsed"`
 	AppliedParametricFilters []AppliedFilter `json:"AppliedParametricFilters"`
+
+	CacheMeta `json:"-"`
 }
 
 // FilterOptions represents available filter options.
@@ -297,4 +341,191 @@ type AppliedFilter struct {
 type ProductDetailsResponse struct {
 	Product          Product      `json:"Product"`
 	SearchLocaleUsed SearchLocale `json:"SearchLocaleUsed"`
+
+	CacheMeta `json:"-"`
+}
+
+// ManufacturersResponse represents the list of all manufacturers.
+type ManufacturersResponse struct {
+	Manufacturers []Manufacturer `json:"Manufacturers"`
+
+	CacheMeta `json:"-"`
+}
+
+// CategoriesResponse represents the top-level category list.
+type CategoriesResponse struct {
+	ProductCount     int          `json:"ProductCount"`
+	Categories       []Category   `json:"Categories"`
+	SearchLocaleUsed SearchLocale `json:"SearchLocaleUsed"`
+
+	CacheMeta `json:"-"`
+}
+
+// CategoryResponse represents a single category and its children.
+type CategoryResponse struct {
+	Category         Category     `json:"Category"`
+	SearchLocaleUsed SearchLocale `json:"SearchLocaleUsed"`
+
+	CacheMeta `json:"-"`
+}
+
+// PackageTypeByQuantityResponse represents the packaging options for a requested quantity.
+type PackageTypeByQuantityResponse struct {
+	Products         []Product    `json:"Products"`
+	SearchLocaleUsed SearchLocale `json:"SearchLocaleUsed"`
+
+	CacheMeta `json:"-"`
+}
+
+// DigiReelPricing represents the pricing of a Digi-Reel for a requested quantity.
+type DigiReelPricing struct {
+	ReelingFee        float64      `json:"ReelingFee"`
+	UnitPrice         float64      `json:"UnitPrice"`
+	ExtendedPrice     float64      `json:"ExtendedPrice"`
+	RequestedQuantity int          `json:"RequestedQuantity"`
+	SearchLocaleUsed  SearchLocale `json:"SearchLocaleUsed"`
+
+	CacheMeta `json:"-"`
+}
+
+// RecommendedProductsResponse represents products recommended alongside a product.
+type RecommendedProductsResponse struct {
+	Recommendations []Recommendation `json:"Recommendations"`
+
+	CacheMeta `json:"-"`
+}
+
+// Recommendation represents the recommendations for one product.
+type Recommendation struct {
+	ProductNumber       string               `json:"ProductNumber"`
+	RecommendedProducts []RecommendedProduct `json:"RecommendedProducts"`
+	SearchLocaleUsed    SearchLocale         `json:"SearchLocaleUsed"`
+}
+
+// RecommendedProduct represents a recommended product.
+type RecommendedProduct struct {
+	DigiKeyProductNumber      string  `json:"DigiKeyProductNumber"`
+	ManufacturerProductNumber string  `json:"ManufacturerProductNumber"`
+	ManufacturerName          string  `json:"ManufacturerName"`
+	PrimaryPhoto              string  `json:"PrimaryPhoto"`
+	ProductDescription        string  `json:"ProductDescription"`
+	QuantityAvailable         int     `json:"QuantityAvailable"`
+	UnitPrice                 float64 `json:"UnitPrice"`
+	ProductURL                string  `json:"ProductUrl"`
+}
+
+// ProductSubstitutesResponse represents the substitutes for a product.
+type ProductSubstitutesResponse struct {
+	ProductSubstitutesCount int                 `json:"ProductSubstitutesCount"`
+	ProductSubstitutes      []ProductSubstitute `json:"ProductSubstitutes"`
+	SearchLocaleUsed        SearchLocale        `json:"SearchLocaleUsed"`
+
+	CacheMeta `json:"-"`
+}
+
+// ProductSubstitute represents a substitute product.
+type ProductSubstitute struct {
+	SubstituteType            string       `json:"SubstituteType"`
+	ProductURL                string       `json:"ProductUrl"`
+	Description               string       `json:"Description"`
+	Manufacturer              Manufacturer `json:"Manufacturer"`
+	ManufacturerProductNumber string       `json:"ManufacturerProductNumber"`
+	UnitOfMeasure             string       `json:"UnitOfMeasure"`
+	QuantityAvailable         int          `json:"QuantityAvailable"`
+	DigiKeyProductNumber      string       `json:"DigiKeyProductNumber"`
+}
+
+// ProductAssociationsResponse represents the products associated with a product.
+type ProductAssociationsResponse struct {
+	ProductAssociations ProductAssociations `json:"ProductAssociations"`
+	SearchLocaleUsed    SearchLocale        `json:"SearchLocaleUsed"`
+
+	CacheMeta `json:"-"`
+}
+
+// ProductAssociations groups associated products by relationship.
+type ProductAssociations struct {
+	Kits               []ProductSummary `json:"Kits"`
+	MatingProducts     []ProductSummary `json:"MatingProducts"`
+	AssociatedProducts []ProductSummary `json:"AssociatedProducts"`
+	ForUseWithProducts []ProductSummary `json:"ForUseWithProducts"`
+}
+
+// ProductSummary represents an abbreviated product.
+type ProductSummary struct {
+	ProductURL                string       `json:"ProductUrl"`
+	ManufacturerProductNumber string       `json:"ManufacturerProductNumber"`
+	Manufacturer              Manufacturer `json:"Manufacturer"`
+	PrimaryPhoto              string       `json:"PrimaryPhoto"`
+	DigiKeyProductNumber      string       `json:"DigiKeyProductNumber"`
+	Description               Description  `json:"Description"`
+	QuantityAvailable         int          `json:"QuantityAvailable"`
+	UnitPrice                 float64      `json:"UnitPrice"`
+}
+
+// MediaResponse represents the media links for a product.
+type MediaResponse struct {
+	MediaLinks []MediaLink `json:"MediaLinks"`
+
+	CacheMeta `json:"-"`
+}
+
+// ProductPricingResponse represents pricing for products matching a product number.
+type ProductPricingResponse struct {
+	ProductPricings []ProductPricing `json:"ProductPricings"`
+	ProductsCount   int              `json:"ProductsCount"`
+	SettingsUsed    SettingsUsed     `json:"SettingsUsed"`
+
+	CacheMeta `json:"-"`
+}
+
+// ProductPricing represents the pricing of a single product.
+type ProductPricing struct {
+	ManufacturerProductNumber string             `json:"ManufacturerProductNumber"`
+	Manufacturer              Manufacturer       `json:"Manufacturer"`
+	Description               Description        `json:"Description"`
+	QuantityAvailable         int                `json:"QuantityAvailable"`
+	ProductURL                string             `json:"ProductUrl"`
+	IsDiscontinued            bool               `json:"IsDiscontinued"`
+	NormallyStocking          bool               `json:"NormallyStocking"`
+	IsObsolete                bool               `json:"IsObsolete"`
+	ProductVariations         []ProductVariation `json:"ProductVariations"`
+}
+
+// SettingsUsed represents the customer and locale settings applied to a pricing request.
+type SettingsUsed struct {
+	CustomerID       int          `json:"CustomerId"`
+	SearchLocaleUsed SearchLocale `json:"SearchLocale"`
+}
+
+// PricingOptionsForQuantityResponse represents the pricing options for a requested quantity.
+type PricingOptionsForQuantityResponse struct {
+	RequestedProduct          string          `json:"RequestedProduct"`
+	RequestedQuantity         int             `json:"RequestedQuantity"`
+	ManufacturerProductNumber string          `json:"ManufacturerPartNumber"`
+	Manufacturer              Manufacturer    `json:"Manufacturer"`
+	Description               Description     `json:"Description"`
+	PricingOptions            []PricingOption `json:"PricingOptions"`
+	SettingsUsed              SettingsUsed    `json:"SettingsUsed"`
+
+	CacheMeta `json:"-"`
+}
+
+// PricingOption represents one way of fulfilling a requested quantity.
+type PricingOption struct {
+	PricingOption       string          `json:"PricingOption"`
+	TotalQuantityPriced int             `json:"TotalQuantityPriced"`
+	TotalPrice          float64         `json:"TotalPrice"`
+	QuantityAvailable   int             `json:"QuantityAvailable"`
+	PricingDetail       []PricingDetail `json:"PricingDetail"`
+}
+
+// PricingDetail represents the packaging breakdown of a pricing option.
+type PricingDetail struct {
+	PackagingType        string  `json:"PackagingType"`
+	DigiKeyProductNumber string  `json:"DigiKeyProductNumber"`
+	UnitPrice            float64 `json:"UnitPrice"`
+	MinimumOrderQuantity int     `json:"MinimumOrderQuantity"`
+	ExtendedPrice        float64 `json:"ExtendedPrice"`
+	QuantityPriced       int     `json:"QuantityPriced"`
 }
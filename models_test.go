@@ -2,6 +2,7 @@ package digikey
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 )
 
@@ -310,3 +311,25 @@ func TestMediaLink(t *testing.T) {
 		t.Errorf("expected URL, got %s", link.URL)
 	}
 }
+
+// TestSearchRequestSortJSON tests the wire format of sort options.
+func TestSearchRequestSortJSON(t *testing.T) {
+	req := SearchRequest{
+		Keywords:    "LED",
+		SortOptions: &SortOptions{Field: SortByQuantityAvailable, Direction: SortDescending},
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	expected := `"SortOptions":{"Field":"QuantityAvailable","SortOrder":"Descending"}`
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("expected %s in %s", expected, data)
+	}
+
+	data, _ = json.Marshal(SearchRequest{Keywords: "LED"})
+	if strings.Contains(string(data), "SortOptions") {
+		t.Errorf("expected SortOptions to be omitted, got %s", data)
+	}
+}
@@ -0,0 +1,156 @@
+package digikey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Token is an OAuth2 token set from the three-legged authorization flow.
+// Save it after logging in and pass it to WithToken to resume the session.
+type Token struct {
+	AccessToken   string    `json:"access_token"`
+	RefreshToken  string    `json:"refresh_token"`
+	Expiry        time.Time `json:"expiry"`         // When the access token expires
+	RefreshExpiry time.Time `json:"refresh_expiry"` // When the refresh token expires; zero if unknown
+}
+
+// AuthCodeURL returns the Digi-Key login URL for the authorization code
+// flow. After the user logs in, Digi-Key redirects to the redirect URL set
+// with WithAuthorizationCode, passing back state and a code for ExchangeCode.
+// state should be unguessable and checked when the redirect arrives.
+func (c *Client) AuthCodeURL(state string) string {
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {c.clientID},
+		"redirect_uri":  {c.tokenManager.redirectURL},
+	}
+	if state != "" {
+		query.Set("state", state)
+	}
+	return c.tokenManager.authorizeURL + "?" + query.Encode()
+}
+
+// ExchangeCode exchanges an authorization code from the redirect for tokens.
+// The client uses them for subsequent requests, refreshing the access token
+// as needed.
+func (c *Client) ExchangeCode(ctx context.Context, code string) (Token, error) {
+	if code == "" {
+		return Token{}, fmt.Errorf("%w: authorization code is required", ErrInvalidRequest)
+	}
+	return c.tokenManager.exchangeCode(ctx, code)
+}
+
+// Token returns the client's current OAuth2 tokens, for example to save
+// them after ExchangeCode.
+func (c *Client) Token() Token {
+	return c.tokenManager.token()
+}
+
+// AuthorizeLoopback runs the authorization code flow for command-line tools.
+// It listens on the host and port of the redirect URL, which must be an
+// http:// loopback address such as http://localhost:8139/callback registered
+// for the app. open receives the login URL and should show it to the user or
+// start a browser. AuthorizeLoopback returns once Digi-Key redirects back and
+// the code has been exchanged, or when ctx ends.
+func (c *Client) AuthorizeLoopback(ctx context.Context, open func(authURL string) error) (Token, error) {
+	redirect, err := url.Parse(c.tokenManager.redirectURL)
+	if err != nil || redirect.Scheme != "http" || !isLoopbackHost(redirect.Hostname()) {
+		return Token{}, fmt.Errorf("%w: redirect URL %q is not an http loopback address", ErrInvalidRequest, c.tokenManager.redirectURL)
+	}
+	port := redirect.Port()
+	if port == "" {
+		port = "80"
+	}
+	callbackPath := redirect.Path
+	if callbackPath == "" {
+		callbackPath = "/"
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return Token{}, err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(redirect.Hostname(), port))
+	if err != nil {
+		return Token{}, fmt.Errorf("digikey: failed to listen for authorization callback: %w", err)
+	}
+
+	type callback struct {
+		code string
+		err  error
+	}
+	callbacks := make(chan callback, 1)
+	server := &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if r.URL.Path != callbackPath || query.Get("state") != state {
+				http.NotFound(w, r)
+				return
+			}
+
+			var cb callback
+			switch {
+			case query.Get("error") != "":
+				cb.err = &AuthError{Err: query.Get("error"), Description: query.Get("error_description")}
+			case query.Get("code") == "":
+				cb.err = &AuthError{Err: "invalid_request", Description: "authorization callback has no code"}
+			default:
+				cb.code = query.Get("code")
+			}
+
+			if cb.err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, "<p>Digi-Key login failed: %s</p>", html.EscapeString(cb.err.Error()))
+			} else {
+				fmt.Fprint(w, "<p>Digi-Key login complete. You can close this window.</p>")
+			}
+			select {
+			case callbacks <- cb:
+			default:
+			}
+		}),
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+
+	if err := open(c.AuthCodeURL(state)); err != nil {
+		return Token{}, err
+	}
+
+	select {
+	case cb := <-callbacks:
+		if cb.err != nil {
+			return Token{}, cb.err
+		}
+		return c.ExchangeCode(ctx, cb.code)
+	case <-ctx.Done():
+		return Token{}, ctx.Err()
+	}
+}
+
+// isLoopbackHost reports whether host names the local machine.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// randomState returns an unguessable OAuth2 state value.
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("digikey: failed to generate state: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
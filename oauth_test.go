@@ -0,0 +1,292 @@
+package digikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// oauthServer is a mock Digi-Key API with an authorization code token endpoint.
+type oauthServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	grants []url.Values // Token requests in order
+	issued int
+}
+
+func newOAuthServer(t *testing.T) *oauthServer {
+	t.Helper()
+
+	s := &oauthServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/oauth2/token" {
+			_ = r.ParseForm()
+			s.mu.Lock()
+			s.grants = append(s.grants, r.PostForm)
+			s.issued++
+			n := s.issued
+			s.mu.Unlock()
+
+			switch r.PostForm.Get("grant_type") {
+			case "authorization_code":
+				if r.PostForm.Get("code") != "good-code" {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"bad code"}`))
+					return
+				}
+			case "refresh_token":
+				if r.PostForm.Get("refresh_token") != fmt.Sprintf("refresh-%d", n-1) {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"bad refresh token"}`))
+					return
+				}
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"unsupported_grant_type"}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"refresh-%d","token_type":"Bearer","expires_in":1800,"refresh_token_expires_in":7776000}`, n, n)
+			return
+		}
+
+		_, _ = fmt.Fprintf(w, `{"Product":{"DigiKeyProductNumber":%q}}`, r.Header.Get("Authorization"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// grant returns the nth token request.
+func (s *oauthServer) grant(n int) url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.grants[n]
+}
+
+// client returns a client using the authorization code flow against s.
+func (s *oauthServer) client(opts ...ClientOption) *Client {
+	opts = append([]ClientOption{
+		WithBaseURL(s.URL),
+		WithTokenURL(s.URL + "/v1/oauth2/token"),
+		WithAuthorizeURL(s.URL + "/v1/oauth2/authorize"),
+		WithAuthorizationCode("https://localhost/callback"),
+		WithoutCache(),
+		WithoutRetry(),
+	}, opts...)
+	return NewClient("test-id", "test-secret", opts...)
+}
+
+// TestAuthCodeURL tests building the login URL.
+func TestAuthCodeURL(t *testing.T) {
+	client := NewClient("test-id", "test-secret", WithAuthorizationCode("https://localhost/callback"))
+
+	u, err := url.Parse(client.AuthCodeURL("xyz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Scheme + "://" + u.Host + u.Path; got != defaultAuthorizeURL {
+		t.Errorf("unexpected authorize URL %s", got)
+	}
+	query := u.Query()
+	want := map[string]string{
+		"response_type": "code",
+		"client_id":     "test-id",
+		"redirect_uri":  "https://localhost/callback",
+		"state":         "xyz",
+	}
+	for k, v := range want {
+		if query.Get(k) != v {
+			t.Errorf("%s = %q, want %q", k, query.Get(k), v)
+		}
+	}
+}
+
+// TestExchangeCodeAndRefresh tests exchanging a code and refreshing the
+// access token with the refresh token.
+func TestExchangeCodeAndRefresh(t *testing.T) {
+	server := newOAuthServer(t)
+	client := server.client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.ProductDetails(ctx, "P5555-ND"); !errors.Is(err, ErrAuthorizationRequired) {
+		t.Fatalf("expected ErrAuthorizationRequired before login, got %v", err)
+	}
+
+	token, err := client.ExchangeCode(ctx, "good-code")
+	if err != nil {
+		t.Fatalf("ExchangeCode: %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" || token.RefreshExpiry.IsZero() {
+		t.Errorf("unexpected token %+v", token)
+	}
+	if g := server.grant(0); g.Get("redirect_uri") != "https://localhost/callback" {
+		t.Errorf("unexpected code exchange %v", g)
+	}
+
+	resp, err := client.ProductDetails(ctx, "P5555-ND")
+	if err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+	if resp.Product.DigiKeyProductNumber != "Bearer access-1" {
+		t.Errorf("expected the exchanged access token, got %q", resp.Product.DigiKeyProductNumber)
+	}
+
+	// Expire the access token; the refresh token is used and rotated
+	client.tokenManager.tokenExpiry = time.Now()
+	resp, err = client.ProductDetails(ctx, "P5555-ND")
+	if err != nil {
+		t.Fatalf("ProductDetails after expiry: %v", err)
+	}
+	if resp.Product.DigiKeyProductNumber != "Bearer access-2" {
+		t.Errorf("expected the refreshed access token, got %q", resp.Product.DigiKeyProductNumber)
+	}
+	if g := server.grant(1); g.Get("grant_type") != "refresh_token" || g.Get("refresh_token") != "refresh-1" {
+		t.Errorf("unexpected refresh request %v", g)
+	}
+	if token := client.Token(); token.RefreshToken != "refresh-2" {
+		t.Errorf("expected rotated refresh token, got %+v", token)
+	}
+}
+
+// TestExchangeCodeError tests a rejected authorization code.
+func TestExchangeCodeError(t *testing.T) {
+	client := newOAuthServer(t).client()
+
+	_, err := client.ExchangeCode(context.Background(), "bad-code")
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || authErr.Err != "invalid_grant" {
+		t.Errorf("expected invalid_grant AuthError, got %v", err)
+	}
+
+	if _, err := client.ExchangeCode(context.Background(), ""); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for empty code, got %v", err)
+	}
+}
+
+// TestWithToken tests resuming a session from a saved token.
+func TestWithToken(t *testing.T) {
+	server := newOAuthServer(t)
+	server.issued = 4 // The saved refresh token is "refresh-4"
+	client := server.client(WithToken(Token{
+		AccessToken:  "saved",
+		RefreshToken: "refresh-4",
+		Expiry:       time.Now().Add(-time.Minute),
+	}))
+
+	resp, err := client.ProductDetails(context.Background(), "P5555-ND")
+	if err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+	if resp.Product.DigiKeyProductNumber != "Bearer access-5" {
+		t.Errorf("expected the refreshed access token, got %q", resp.Product.DigiKeyProductNumber)
+	}
+}
+
+// TestWithTokenRefreshExpired tests that an expired refresh token requires
+// logging in again.
+func TestWithTokenRefreshExpired(t *testing.T) {
+	client := newOAuthServer(t).client(WithToken(Token{
+		AccessToken:   "saved",
+		RefreshToken:  "refresh-1",
+		Expiry:        time.Now().Add(-time.Minute),
+		RefreshExpiry: time.Now().Add(-time.Second),
+	}))
+
+	if _, err := client.ProductDetails(context.Background(), "P5555-ND"); !errors.Is(err, ErrAuthorizationRequired) {
+		t.Errorf("expected ErrAuthorizationRequired, got %v", err)
+	}
+}
+
+// freeLoopbackPort returns a port that is currently free on 127.0.0.1.
+func freeLoopbackPort(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = l.Close() }()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	return port
+}
+
+// TestAuthorizeLoopback tests the loopback callback helper.
+func TestAuthorizeLoopback(t *testing.T) {
+	redirectURL := "http://127.0.0.1:" + freeLoopbackPort(t) + "/callback"
+	client := newOAuthServer(t).client(WithAuthorizationCode(redirectURL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, err := client.AuthorizeLoopback(ctx, func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		state := u.Query().Get("state")
+		if state == "" {
+			return errors.New("missing state")
+		}
+
+		// Simulate the browser following Digi-Key's redirect. A wrong state
+		// is ignored.
+		go func() {
+			for _, s := range []string{"forged", state} {
+				resp, err := http.Get(redirectURL + "?code=good-code&state=" + s)
+				if err == nil {
+					_ = resp.Body.Close()
+				}
+			}
+		}()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("AuthorizeLoopback: %v", err)
+	}
+	if token.AccessToken != "access-1" {
+		t.Errorf("unexpected token %+v", token)
+	}
+}
+
+// TestAuthorizeLoopbackDenied tests a login the user declined.
+func TestAuthorizeLoopbackDenied(t *testing.T) {
+	redirectURL := "http://127.0.0.1:" + freeLoopbackPort(t) + "/callback"
+	client := newOAuthServer(t).client(WithAuthorizationCode(redirectURL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := client.AuthorizeLoopback(ctx, func(authURL string) error {
+		u, _ := url.Parse(authURL)
+		go func() {
+			resp, err := http.Get(redirectURL + "?error=access_denied&state=" + u.Query().Get("state"))
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+		}()
+		return nil
+	})
+	var authErr *AuthError
+	if !errors.As(err, &authErr) || authErr.Err != "access_denied" {
+		t.Errorf("expected access_denied AuthError, got %v", err)
+	}
+}
+
+// TestAuthorizeLoopbackRequiresLoopback tests rejecting non-loopback redirects.
+func TestAuthorizeLoopbackRequiresLoopback(t *testing.T) {
+	client := NewClient("id", "secret", WithAuthorizationCode("https://example.com/callback"))
+
+	_, err := client.AuthorizeLoopback(context.Background(), func(string) error { return nil })
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest, got %v", err)
+	}
+}
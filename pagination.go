@@ -0,0 +1,162 @@
+package digikey
+
+import (
+	"context"
+	"strconv"
+)
+
+// maxSearchLimit is the largest page size accepted by KeywordSearch.
+const maxSearchLimit = 50
+
+// SearchIterator pages through KeywordSearch results lazily.
+//
+//	it := client.SearchIterator(req, 500)
+//	for it.Next(ctx) {
+//	    product := it.Product()
+//	}
+//	if err := it.Err(); err != nil {
+//	    // handle error
+//	}
+type SearchIterator struct {
+	client  *Client
+	request SearchRequest
+	max     int
+
+	page     []Product
+	pos      int
+	current  Product
+	seen     map[string]bool
+	returned int
+	total    int
+	done     bool
+	err      error
+}
+
+// SearchIterator returns an iterator over all products matching req, starting
+// at req.Offset. Pages are fetched with req.Limit (default 50) as results are
+// consumed. max caps the number of products returned; 0 means no cap.
+func (c *Client) SearchIterator(req *SearchRequest, max int) *SearchIterator {
+	it := &SearchIterator{
+		client: c,
+		max:    max,
+		seen:   make(map[string]bool),
+		total:  -1,
+	}
+	if req == nil {
+		it.err = ErrInvalidRequest
+		it.done = true
+		return it
+	}
+
+	it.request = *req
+	if it.request.Limit <= 0 || it.request.Limit > maxSearchLimit {
+		it.request.Limit = maxSearchLimit
+	}
+	if it.request.Offset < 0 {
+		it.request.Offset = 0
+	}
+	return it
+}
+
+// Next advances to the next product, fetching another page if needed.
+// It returns false when results are exhausted, max is reached, or an error occurs.
+// Page requests queue for a rate limit slot within the minute window; once
+// the day budget is used up, iteration stops with a *RateLimitError.
+func (it *SearchIterator) Next(ctx context.Context) bool {
+	for {
+		if it.done || (it.max > 0 && it.returned >= it.max) {
+			it.done = true
+			return false
+		}
+
+		for it.pos < len(it.page) {
+			product := it.page[it.pos]
+			it.pos++
+
+			key := productKey(product)
+			if key != "" && it.seen[key] {
+				continue
+			}
+			if key != "" {
+				it.seen[key] = true
+			}
+
+			it.current = product
+			it.returned++
+			return true
+		}
+
+		if !it.fetch(ctx) {
+			return false
+		}
+	}
+}
+
+// fetch loads the next page of results.
+func (it *SearchIterator) fetch(ctx context.Context) bool {
+	if it.total >= 0 && it.request.Offset >= it.total {
+		it.done = true
+		return false
+	}
+
+	// Queue for a rate limit slot rather than failing mid-iteration, unless
+	// the day budget is used up
+	resp, err := it.client.KeywordSearch(withRateLimitQueue(ctx), &it.request)
+	if err != nil {
+		it.err = err
+		it.done = true
+		return false
+	}
+
+	it.total = resp.ProductsCount
+	it.page = resp.Products
+	it.pos = 0
+	it.request.Offset += len(resp.Products)
+
+	if len(resp.Products) == 0 {
+		it.done = true
+		return false
+	}
+	return true
+}
+
+// Product returns the current product.
+func (it *SearchIterator) Product() Product {
+	return it.current
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+// Total returns the total number of matching products reported by the API,
+// or -1 if no page has been fetched yet.
+func (it *SearchIterator) Total() int {
+	return it.total
+}
+
+// SearchAll collects up to max products matching req across pages.
+// If an error occurs, the products collected so far are returned with it.
+// Like SearchIterator, it fails with a *RateLimitError rather than waiting
+// for the day budget to reset.
+func (c *Client) SearchAll(ctx context.Context, req *SearchRequest, max int) ([]Product, error) {
+	it := c.SearchIterator(req, max)
+
+	var products []Product
+	for it.Next(ctx) {
+		products = append(products, it.Product())
+	}
+	return products, it.Err()
+}
+
+// productKey identifies a product for deduplication across pages.
+func productKey(p Product) string {
+	if p.DigiKeyProductNumber != "" {
+		return p.DigiKeyProductNumber
+	}
+	if p.ManufacturerProductNumber != "" {
+		return strconv.Itoa(p.Manufacturer.ID) + ":" + p.ManufacturerProductNumber
+	}
+	return ""
+}
@@ -0,0 +1,32 @@
+//go:build go1.23
+
+package digikey
+
+import (
+	"context"
+	"iter"
+)
+
+// SearchSeq returns an iterator over up to max products matching req,
+// fetching pages lazily. Iteration stops after the first error, which is
+// yielded with a zero Product.
+//
+//	for product, err := range client.SearchSeq(ctx, req, 500) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    fmt.Println(product.ManufacturerProductNumber)
+//	}
+func (c *Client) SearchSeq(ctx context.Context, req *SearchRequest, max int) iter.Seq2[Product, error] {
+	return func(yield func(Product, error) bool) {
+		it := c.SearchIterator(req, max)
+		for it.Next(ctx) {
+			if !yield(it.Product(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(Product{}, err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+//go:build go1.23
+
+package digikey
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestSearchSeq tests range-over-func iteration with early break.
+func TestSearchSeq(t *testing.T) {
+	calls := 0
+	client := newPagingClient(t, 200, false, &calls)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count := 0
+	for product, err := range client.SearchSeq(ctx, &SearchRequest{Keywords: "resistor"}, 0) {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if product.DigiKeyProductNumber == "" {
+			t.Error("expected product number")
+		}
+		count++
+		if count == 75 {
+			break
+		}
+	}
+
+	if count != 75 {
+		t.Errorf("expected 75 products, got %d", count)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 page requests, got %d", calls)
+	}
+}
@@ -0,0 +1,203 @@
+package digikey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// newPagingClient serves a keyword search over total products. If shift is
+// true, every page after the first repeats the last product of the previous page.
+func newPagingClient(t *testing.T, total int, shift bool, calls *int) *Client {
+	t.Helper()
+
+	return newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+
+		var req SearchRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		start := req.Offset
+		if shift && start > 0 {
+			start--
+		}
+		var products []Product
+		for i := start; i < start+req.Limit && i < total; i++ {
+			products = append(products, Product{DigiKeyProductNumber: fmt.Sprintf("PN-%03d", i)})
+		}
+
+		_ = json.NewEncoder(w).Encode(SearchResponse{Products: products, ProductsCount: total})
+	}, WithoutCache())
+}
+
+// TestSearchAll tests collecting all results across pages.
+func TestSearchAll(t *testing.T) {
+	calls := 0
+	client := newPagingClient(t, 120, false, &calls)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	products, err := client.SearchAll(ctx, &SearchRequest{Keywords: "resistor"}, 0)
+	if err != nil {
+		t.Fatalf("SearchAll failed: %v", err)
+	}
+	if len(products) != 120 {
+		t.Errorf("expected 120 products, got %d", len(products))
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 page requests, got %d", calls)
+	}
+}
+
+// TestSearchAllMax tests that max stops paging early.
+func TestSearchAllMax(t *testing.T) {
+	calls := 0
+	client := newPagingClient(t, 500, false, &calls)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	products, err := client.SearchAll(ctx, &SearchRequest{Keywords: "resistor", Limit: 20}, 45)
+	if err != nil {
+		t.Fatalf("SearchAll failed: %v", err)
+	}
+	if len(products) != 45 {
+		t.Errorf("expected 45 products, got %d", len(products))
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 page requests, got %d", calls)
+	}
+}
+
+// TestSearchIteratorDeduplicates tests that products repeated across pages are skipped.
+func TestSearchIteratorDeduplicates(t *testing.T) {
+	calls := 0
+	client := newPagingClient(t, 100, true, &calls)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	it := client.SearchIterator(&SearchRequest{Keywords: "resistor"}, 0)
+	seen := make(map[string]bool)
+	for it.Next(ctx) {
+		pn := it.Product().DigiKeyProductNumber
+		if seen[pn] {
+			t.Errorf("duplicate product %s", pn)
+		}
+		seen[pn] = true
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("iteration failed: %v", err)
+	}
+	if it.Total() != 100 {
+		t.Errorf("expected total 100, got %d", it.Total())
+	}
+}
+
+// TestSearchIteratorError tests that errors stop iteration.
+func TestSearchIteratorError(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"bad keywords"}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	products, err := client.SearchAll(ctx, &SearchRequest{Keywords: "x"}, 0)
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest, got %v", err)
+	}
+	if len(products) != 0 {
+		t.Errorf("expected no products, got %d", len(products))
+	}
+
+	it := client.SearchIterator(nil, 0)
+	if it.Next(ctx) {
+		t.Error("expected nil request to stop iteration")
+	}
+	if !errors.Is(it.Err(), ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest, got %v", it.Err())
+	}
+}
+
+// TestSearchIteratorWaitsForRateLimit tests that paging waits for the minute window.
+func TestSearchIteratorWaitsForRateLimit(t *testing.T) {
+	calls := 0
+	client := newPagingClient(t, 100, false, &calls)
+	limiter := NewRateLimiterWithLimits(1, 100)
+	limiter.minuteResetTime = time.Now().Add(100 * time.Millisecond)
+	client.rateLimiter = limiter
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	products, err := client.SearchAll(ctx, &SearchRequest{Keywords: "resistor"}, 60)
+	if err != nil {
+		t.Fatalf("SearchAll failed: %v", err)
+	}
+	if len(products) != 60 {
+		t.Errorf("expected 60 products, got %d", len(products))
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected second page to wait for rate limit, took %v", elapsed)
+	}
+}
+
+// TestSearchIteratorConcurrentRateLimit tests that concurrent iterators
+// queue for slots instead of failing when another takes the free one.
+func TestSearchIteratorConcurrentRateLimit(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(SearchResponse{
+			Products:      []Product{{DigiKeyProductNumber: "PN-000"}},
+			ProductsCount: 1,
+		})
+	}, WithoutCache())
+	limiter := NewRateLimiterWithLimits(1, 100)
+	limiter.minuteResetTime = time.Now().Add(50 * time.Millisecond)
+	client.rateLimiter = limiter
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errs := make(chan error, 2)
+	for _, keywords := range []string{"resistor", "capacitor"} {
+		go func() {
+			_, err := client.SearchAll(ctx, &SearchRequest{Keywords: keywords}, 10)
+			errs <- err
+		}()
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("SearchAll failed: %v", err)
+		}
+	}
+}
+
+// TestSearchAllDayLimit tests that paging stops with the day limit instead
+// of queueing until it resets.
+func TestSearchAllDayLimit(t *testing.T) {
+	calls := 0
+	client := newPagingClient(t, 100, false, &calls)
+	client.rateLimiter = NewRateLimiterWithLimits(100, 1)
+
+	start := time.Now()
+	products, err := client.SearchAll(context.Background(), &SearchRequest{Keywords: "resistor"}, 0)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("SearchAll waited %v for the day limit", elapsed)
+	}
+
+	var rateErr *RateLimitError
+	if !errors.As(err, &rateErr) || rateErr.Type != "day" {
+		t.Errorf("expected a day RateLimitError, got %v", err)
+	}
+	if len(products) != 50 {
+		t.Errorf("expected the first page of 50 products, got %d", len(products))
+	}
+}
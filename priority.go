@@ -0,0 +1,262 @@
+package digikey
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Priority classifies requests so a PriorityLimiter can hold capacity back
+// for the important ones.
+type Priority int
+
+// Request priorities. Requests without a priority are PriorityNormal.
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+// String returns the priority name.
+func (p Priority) String() string {
+	switch p.clamp() {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+// clamp maps out-of-range values to the nearest defined priority.
+func (p Priority) clamp() Priority {
+	return min(max(p, PriorityLow), PriorityHigh)
+}
+
+// priorityKey is the context key for request priorities.
+type priorityKey struct{}
+
+// WithPriority returns a context whose requests are made at priority p.
+//
+//	ctx = digikey.WithPriority(ctx, digikey.PriorityLow)
+//	resp, err := client.ProductDetails(ctx, pn)
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority set with WithPriority, or
+// PriorityNormal.
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p.clamp()
+	}
+	return PriorityNormal
+}
+
+// PriorityReserve is capacity that lower-priority requests may not use.
+type PriorityReserve struct {
+	Minute int // Requests per minute
+	Day    int // Requests per day
+}
+
+// PriorityStats counts requests of one priority class.
+type PriorityStats struct {
+	Allowed  int // Requests allowed
+	Rejected int // Requests refused by Allow, including retries while waiting
+	Waiting  int // Requests currently queued in Wait
+}
+
+// PriorityLimiter wraps a Limiter so that capacity is held back for
+// higher-priority requests. A request is refused when the remaining minute
+// or day capacity is within the combined reserves of the classes above it.
+// Each class queues separately in Wait, so waiting low-priority requests
+// never hold up high-priority ones. Digi-Key's rate limit headers are
+// forwarded to the wrapped limiter if it implements RateLimitSyncer.
+//
+//	limiter := digikey.NewPriorityLimiter(digikey.NewRateLimiter(), map[digikey.Priority]digikey.PriorityReserve{
+//	    digikey.PriorityHigh:   {Minute: 20, Day: 100},
+//	    digikey.PriorityNormal: {Minute: 20},
+//	})
+type PriorityLimiter struct {
+	inner    Limiter
+	reserves map[Priority]PriorityReserve
+
+	mu       sync.Mutex
+	stats    map[Priority]*PriorityStats
+	queues   map[Priority]*waitQueue
+	inflight int    // Requests between their reserve check and inner.Allow returning
+	finished uint64 // Requests that have left AllowPriority
+}
+
+// NewPriorityLimiter wraps inner, or a default RateLimiter if inner is nil.
+// reserves maps each priority to the capacity only it and higher
+// priorities may use.
+func NewPriorityLimiter(inner Limiter, reserves map[Priority]PriorityReserve) *PriorityLimiter {
+	if inner == nil {
+		inner = NewRateLimiter()
+	}
+
+	l := &PriorityLimiter{
+		inner:    inner,
+		reserves: make(map[Priority]PriorityReserve),
+		stats:    make(map[Priority]*PriorityStats),
+		queues:   make(map[Priority]*waitQueue),
+	}
+	for p, r := range reserves {
+		l.reserves[p.clamp()] = r
+	}
+	for _, p := range []Priority{PriorityLow, PriorityNormal, PriorityHigh} {
+		l.stats[p] = &PriorityStats{}
+		l.queues[p] = &waitQueue{}
+	}
+	return l
+}
+
+// Allow counts a normal-priority request.
+func (l *PriorityLimiter) Allow() error {
+	return l.AllowPriority(PriorityNormal)
+}
+
+// AllowPriority counts a request of priority p if capacity outside the
+// reserves of higher priorities remains.
+func (l *PriorityLimiter) AllowPriority(p Priority) error {
+	p = p.clamp()
+
+	// The wrapped limiter may do network I/O, so l.mu only guards the
+	// bookkeeping around it
+	l.mu.Lock()
+	l.inflight++
+	finished := l.finished
+	l.mu.Unlock()
+
+	err := l.reserved(p, finished)
+	if err == nil {
+		err = l.inner.Allow()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inflight--
+	l.finished++
+	if err != nil {
+		l.stats[p].Rejected++
+		return err
+	}
+	l.stats[p].Allowed++
+	return nil
+}
+
+// Wait blocks until a request at the context's priority is allowed.
+func (l *PriorityLimiter) Wait(ctx context.Context) error {
+	p := PriorityFromContext(ctx)
+
+	l.mu.Lock()
+	l.stats[p].Waiting++
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		l.stats[p].Waiting--
+		l.mu.Unlock()
+	}()
+
+	return l.queues[p].wait(ctx,
+		func() error { return l.AllowPriority(p) },
+		func() time.Duration { return l.waitTime(p) },
+	)
+}
+
+// WaitTime returns how long until a normal-priority request is allowed.
+func (l *PriorityLimiter) WaitTime() time.Duration {
+	return l.waitTime(PriorityNormal)
+}
+
+// Stats returns the wrapped limiter's stats with per-priority counts.
+func (l *PriorityLimiter) Stats() RateLimitStats {
+	stats := l.inner.Stats()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats.Priorities = make(map[Priority]PriorityStats, len(l.stats))
+	for p, s := range l.stats {
+		stats.Priorities[p] = *s
+	}
+	return stats
+}
+
+// UpdateFromResponse passes a 429 Retry-After on to the wrapped limiter.
+func (l *PriorityLimiter) UpdateFromResponse(retryAfterSeconds int) {
+	l.inner.UpdateFromResponse(retryAfterSeconds)
+}
+
+// SyncFromServer passes server-reported usage on to the wrapped limiter if
+// it implements RateLimitSyncer.
+func (l *PriorityLimiter) SyncFromServer(limit ServerRateLimit) {
+	if syncer, ok := l.inner.(RateLimitSyncer); ok {
+		syncer.SyncFromServer(limit)
+	}
+}
+
+// reserved returns a RateLimitError if the remaining capacity is held back
+// for priorities above p. finished is l.finished when the request started.
+// Requests that other goroutines are admitting, or admitted since then, may
+// not show in the wrapped limiter's stats yet, so they are counted against
+// the remaining capacity; at worst this counts a request twice.
+func (l *PriorityLimiter) reserved(p Priority, finished uint64) error {
+	minute, day := l.reserveAbove(p)
+	if minute == 0 && day == 0 {
+		return nil
+	}
+
+	stats := l.inner.Stats()
+
+	l.mu.Lock()
+	pending := l.inflight - 1 + int(l.finished-finished)
+	l.mu.Unlock()
+
+	if stats.MinuteRemaining-pending <= minute {
+		return newRateLimitError(stats.MinuteLimit, stats.MinuteResetAt, "minute")
+	}
+	if stats.DayRemaining-pending <= day {
+		return newRateLimitError(stats.DayLimit, stats.DayResetAt, "day")
+	}
+	return nil
+}
+
+// waitTime returns how long until a request of priority p may be allowed.
+func (l *PriorityLimiter) waitTime(p Priority) time.Duration {
+	wait := l.inner.WaitTime()
+
+	minute, day := l.reserveAbove(p)
+	if minute == 0 && day == 0 {
+		return wait
+	}
+
+	stats := l.inner.Stats()
+	if stats.MinuteRemaining <= minute {
+		wait = max(wait, time.Until(stats.MinuteResetAt))
+	}
+	if stats.DayRemaining <= day {
+		wait = max(wait, time.Until(stats.DayResetAt))
+	}
+	return wait
+}
+
+// reserveAbove returns the combined reserves of priorities above p.
+func (l *PriorityLimiter) reserveAbove(p Priority) (minute, day int) {
+	for q, r := range l.reserves {
+		if q > p {
+			minute += r.Minute
+			day += r.Day
+		}
+	}
+	return minute, day
+}
+
+// priorityAllower is implemented by limiters that take a priority in Allow.
+type priorityAllower interface {
+	AllowPriority(p Priority) error
+}
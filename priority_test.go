@@ -0,0 +1,200 @@
+package digikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestPriorityFromContext tests storing priorities in a context.
+func TestPriorityFromContext(t *testing.T) {
+	ctx := context.Background()
+	if p := PriorityFromContext(ctx); p != PriorityNormal {
+		t.Errorf("expected default PriorityNormal, got %v", p)
+	}
+	if p := PriorityFromContext(WithPriority(ctx, PriorityHigh)); p != PriorityHigh {
+		t.Errorf("expected PriorityHigh, got %v", p)
+	}
+	if p := PriorityFromContext(WithPriority(ctx, Priority(-5))); p != PriorityLow {
+		t.Errorf("expected out-of-range priority to clamp to PriorityLow, got %v", p)
+	}
+	if s := PriorityHigh.String(); s != "high" {
+		t.Errorf("expected \"high\", got %q", s)
+	}
+}
+
+// TestPriorityLimiterReserves tests that lower classes cannot use reserved capacity.
+func TestPriorityLimiterReserves(t *testing.T) {
+	l := NewPriorityLimiter(NewRateLimiterWithLimits(10, 1000), map[Priority]PriorityReserve{
+		PriorityHigh:   {Minute: 2},
+		PriorityNormal: {Minute: 3},
+	})
+
+	allowed := func(p Priority) int {
+		n := 0
+		for l.AllowPriority(p) == nil {
+			n++
+		}
+		return n
+	}
+
+	if n := allowed(PriorityLow); n != 5 {
+		t.Errorf("expected low priority to get 5 requests, got %d", n)
+	}
+	if n := allowed(PriorityNormal); n != 3 {
+		t.Errorf("expected normal priority to get 3 more requests, got %d", n)
+	}
+	if n := allowed(PriorityHigh); n != 2 {
+		t.Errorf("expected high priority to get the last 2 requests, got %d", n)
+	}
+
+	stats := l.Stats()
+	if stats.MinuteUsed != 10 {
+		t.Errorf("expected 10 requests used, got %d", stats.MinuteUsed)
+	}
+	if s := stats.Priorities[PriorityLow]; s.Allowed != 5 || s.Rejected != 1 {
+		t.Errorf("unexpected low priority stats %+v", s)
+	}
+	if s := stats.Priorities[PriorityHigh]; s.Allowed != 2 || s.Rejected != 1 {
+		t.Errorf("unexpected high priority stats %+v", s)
+	}
+}
+
+// slowLimiter is a Limiter whose Stats and Allow take a while, like a
+// SharedLimiter backed by a remote store.
+type slowLimiter struct {
+	Limiter
+	delay time.Duration
+}
+
+func (l slowLimiter) Allow() error {
+	time.Sleep(l.delay)
+	return l.Limiter.Allow()
+}
+
+func (l slowLimiter) Stats() RateLimitStats {
+	time.Sleep(l.delay)
+	return l.Limiter.Stats()
+}
+
+// TestPriorityLimiterConcurrent tests that slow wrapped limiters are called
+// concurrently and that concurrent requests still leave the reserve alone.
+func TestPriorityLimiterConcurrent(t *testing.T) {
+	l := NewPriorityLimiter(slowLimiter{NewRateLimiterWithLimits(30, 1000), 20 * time.Millisecond}, map[Priority]PriorityReserve{
+		PriorityHigh: {Minute: 20},
+	})
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = l.AllowPriority(PriorityLow)
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Errorf("expected concurrent calls to the wrapped limiter, took %v", elapsed)
+	}
+	if s := l.Stats().Priorities[PriorityLow]; s.Allowed > 10 {
+		t.Errorf("low priority used the high reserve: %+v", s)
+	}
+	if err := l.AllowPriority(PriorityHigh); err != nil {
+		t.Errorf("expected high priority to be allowed, got %v", err)
+	}
+}
+
+// TestPriorityLimiterDayReserve tests reserving daily capacity.
+func TestPriorityLimiterDayReserve(t *testing.T) {
+	l := NewPriorityLimiter(NewRateLimiterWithLimits(100, 3), map[Priority]PriorityReserve{
+		PriorityHigh: {Day: 1},
+	})
+
+	_ = l.Allow()
+	_ = l.Allow()
+
+	err := l.Allow()
+	var rle *RateLimitError
+	if !errors.As(err, &rle) || rle.Type != "day" {
+		t.Fatalf("expected day RateLimitError, got %v", err)
+	}
+	if err := l.AllowPriority(PriorityHigh); err != nil {
+		t.Errorf("expected high priority to use the reserve, got %v", err)
+	}
+}
+
+// TestPriorityLimiterWait tests that waiting low-priority requests do not
+// hold up high-priority ones.
+func TestPriorityLimiterWait(t *testing.T) {
+	inner := NewRateLimiterWithLimits(3, 1000)
+	l := NewPriorityLimiter(inner, map[Priority]PriorityReserve{
+		PriorityHigh: {Minute: 1},
+	})
+	_ = l.Allow()
+	_ = l.Allow()
+
+	// Open a new minute window shortly
+	inner.mu.Lock()
+	inner.minuteResetTime = time.Now().Add(100 * time.Millisecond)
+	inner.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- l.Wait(WithPriority(ctx, PriorityLow)) }()
+
+	for l.Stats().Priorities[PriorityLow].Waiting != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := l.Wait(WithPriority(ctx, PriorityHigh)); err != nil {
+		t.Fatalf("high priority Wait: %v", err)
+	}
+	select {
+	case err := <-done:
+		t.Fatalf("low priority request should still be waiting, got %v", err)
+	default:
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("low priority Wait: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("low priority request did not proceed after the window reset")
+	}
+
+	if s := l.Stats().Priorities[PriorityLow]; s.Waiting != 0 || s.Allowed != 1 {
+		t.Errorf("unexpected low priority stats %+v", s)
+	}
+}
+
+// TestClientRequestPriority tests that the client passes the context priority on.
+func TestClientRequestPriority(t *testing.T) {
+	limiter := NewPriorityLimiter(NewRateLimiterWithLimits(2, 1000), map[Priority]PriorityReserve{
+		PriorityHigh: {Minute: 1},
+	})
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"Product":{}}`))
+	}, WithoutCache(), WithRateLimiter(limiter))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := client.ProductDetails(ctx, "A"); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	if _, err := client.ProductDetails(WithPriority(ctx, PriorityLow), "B"); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Errorf("expected low priority request to be refused, got %v", err)
+	}
+	if _, err := client.ProductDetails(WithPriority(ctx, PriorityHigh), "C"); err != nil {
+		t.Errorf("expected high priority request to use the reserve, got %v", err)
+	}
+}
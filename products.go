@@ -2,10 +2,12 @@ package digikey
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 )
 
 const (
@@ -31,31 +33,13 @@ func (c *Client) KeywordSearch(ctx context.Context, req *SearchRequest) (*Search
 		searchReq.Limit = 50
 	}
 
-	// Check cache
-	if c.cacheConfig.Enabled && c.cache != nil {
-		cacheKey := cacheKeyForSearch(c.getLocale(), &searchReq)
-		if cached, ok := c.cache.Get(cacheKey); ok {
-			var resp SearchResponse
-			if err := json.Unmarshal(cached, &resp); err == nil {
-				return &resp, nil
-			}
-		}
-	}
-
 	var resp SearchResponse
-	err := c.do(ctx, http.MethodPost, searchBasePath+"/keyword", &searchReq, &resp)
+	cacheKey := c.environment.cacheKey(cacheKeyForSearch(c.getLocale(), &searchReq))
+	err := c.cachedDo(ctx, http.MethodPost, searchBasePath+"/keyword", &searchReq, cacheKey, c.cacheConfig.SearchTTL, &resp)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store in cache
-	if c.cacheConfig.Enabled && c.cache != nil {
-		if data, err := json.Marshal(resp); err == nil {
-			cacheKey := cacheKeyForSearch(c.getLocale(), &searchReq)
-			c.cache.Set(cacheKey, data, c.cacheConfig.SearchTTL)
-		}
-	}
-
 	return &resp, nil
 }
 
@@ -65,33 +49,15 @@ func (c *Client) ProductDetails(ctx context.Context, productNumber string) (*Pro
 		return nil, fmt.Errorf("%w: product number is required", ErrInvalidRequest)
 	}
 
-	// Check cache
-	if c.cacheConfig.Enabled && c.cache != nil {
-		cacheKey := cacheKeyForDetails(c.getLocale(), productNumber)
-		if cached, ok := c.cache.Get(cacheKey); ok {
-			var resp ProductDetailsResponse
-			if err := json.Unmarshal(cached, &resp); err == nil {
-				return &resp, nil
-			}
-		}
-	}
-
 	path := fmt.Sprintf("%s/%s/productdetails", searchBasePath, url.PathEscape(productNumber))
 
 	var resp ProductDetailsResponse
-	err := c.do(ctx, http.MethodGet, path, nil, &resp)
+	cacheKey := c.environment.cacheKey(cacheKeyForDetails(c.getLocale(), productNumber))
+	err := c.cachedDo(ctx, http.MethodGet, path, nil, cacheKey, c.cacheConfig.DetailsTTL, &resp)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store in cache
-	if c.cacheConfig.Enabled && c.cache != nil {
-		if data, err := json.Marshal(resp); err == nil {
-			cacheKey := cacheKeyForDetails(c.getLocale(), productNumber)
-			c.cache.Set(cacheKey, data, c.cacheConfig.DetailsTTL)
-		}
-	}
-
 	return &resp, nil
 }
 
@@ -111,19 +77,276 @@ func (c *Client) ProductDetailsNoCache(ctx context.Context, productNumber string
 	}
 
 	// Update cache with fresh data
-	if c.cacheConfig.Enabled && c.cache != nil {
-		if data, err := json.Marshal(resp); err == nil {
-			cacheKey := cacheKeyForDetails(c.getLocale(), productNumber)
-			c.cache.Set(cacheKey, data, c.cacheConfig.DetailsTTL)
+	c.storeCache(c.environment.cacheKey(cacheKeyForDetails(c.getLocale(), productNumber)), c.cacheConfig.DetailsTTL, &resp)
+
+	return &resp, nil
+}
+
+// Manufacturers retrieves all manufacturers known to Digi-Key.
+func (c *Client) Manufacturers(ctx context.Context) (*ManufacturersResponse, error) {
+	var resp ManufacturersResponse
+	if err := c.cachedGet(ctx, "manufacturers", searchBasePath+"/manufacturers", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Categories retrieves the top-level product categories and their children.
+func (c *Client) Categories(ctx context.Context) (*CategoriesResponse, error) {
+	var resp CategoriesResponse
+	if err := c.cachedGet(ctx, "categories", searchBasePath+"/categories", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Category retrieves a single category and its children by ID.
+func (c *Client) Category(ctx context.Context, categoryID int) (*CategoryResponse, error) {
+	if categoryID <= 0 {
+		return nil, fmt.Errorf("%w: category ID must be positive", ErrInvalidRequest)
+	}
+
+	path := fmt.Sprintf("%s/categories/%d", searchBasePath, categoryID)
+
+	var resp CategoryResponse
+	if err := c.cachedGet(ctx, "categories", path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PackageTypeByQuantity retrieves the packaging options for a product at the
+// requested quantity. packagingPreference is optional.
+func (c *Client) PackageTypeByQuantity(ctx context.Context, productNumber string, quantity int, packagingPreference string) (*PackageTypeByQuantityResponse, error) {
+	if productNumber == "" {
+		return nil, fmt.Errorf("%w: product number is required", ErrInvalidRequest)
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidRequest)
+	}
+
+	query := url.Values{"requestedQuantity": {strconv.Itoa(quantity)}}
+	if packagingPreference != "" {
+		query.Set("packagingPreference", packagingPreference)
+	}
+	path := fmt.Sprintf("%s/packagetypebyquantity/%s?%s", searchBasePath, url.PathEscape(productNumber), query.Encode())
+
+	var resp PackageTypeByQuantityResponse
+	if err := c.cachedGet(ctx, "packagetype", path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DigiReelPricing retrieves the Digi-Reel pricing for a product at the requested quantity.
+func (c *Client) DigiReelPricing(ctx context.Context, productNumber string, quantity int) (*DigiReelPricing, error) {
+	if productNumber == "" {
+		return nil, fmt.Errorf("%w: product number is required", ErrInvalidRequest)
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidRequest)
+	}
+
+	path := fmt.Sprintf("%s/%s/digireelpricing?requestedQuantity=%d", searchBasePath, url.PathEscape(productNumber), quantity)
+
+	var resp DigiReelPricing
+	if err := c.cachedGet(ctx, "digireel", path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RecommendedProductsOptions configures a recommended products request.
+type RecommendedProductsOptions struct {
+	Limit              int      // Maximum number of recommendations (1-50)
+	SearchOptions      []string // Search options such as "InStock"
+	ExcludeMarketplace bool     // Exclude marketplace products
+}
+
+// RecommendedProducts retrieves products commonly purchased with a product.
+// opts may be nil.
+func (c *Client) RecommendedProducts(ctx context.Context, productNumber string, opts *RecommendedProductsOptions) (*RecommendedProductsResponse, error) {
+	if productNumber == "" {
+		return nil, fmt.Errorf("%w: product number is required", ErrInvalidRequest)
+	}
+
+	query := url.Values{}
+	if opts != nil {
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if len(opts.SearchOptions) > 0 {
+			query.Set("searchOptionList", strings.Join(opts.SearchOptions, ","))
+		}
+		if opts.ExcludeMarketplace {
+			query.Set("excludeMarketPlaceProducts", "true")
 		}
 	}
+	path := withQuery(fmt.Sprintf("%s/%s/recommendedproducts", searchBasePath, url.PathEscape(productNumber)), query)
 
+	var resp RecommendedProductsResponse
+	if err := c.cachedGet(ctx, "recommended", path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Substitutions retrieves substitute products for a product.
+func (c *Client) Substitutions(ctx context.Context, productNumber string) (*ProductSubstitutesResponse, error) {
+	if productNumber == "" {
+		return nil, fmt.Errorf("%w: product number is required", ErrInvalidRequest)
+	}
+
+	path := fmt.Sprintf("%s/%s/substitutions", searchBasePath, url.PathEscape(productNumber))
+
+	var resp ProductSubstitutesResponse
+	if err := c.cachedGet(ctx, "substitutions", path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Associations retrieves kits, mating products and other products associated with a product.
+func (c *Client) Associations(ctx context.Context, productNumber string) (*ProductAssociationsResponse, error) {
+	if productNumber == "" {
+		return nil, fmt.Errorf("%w: product number is required", ErrInvalidRequest)
+	}
+
+	path := fmt.Sprintf("%s/%s/associations", searchBasePath, url.PathEscape(productNumber))
+
+	var resp ProductAssociationsResponse
+	if err := c.cachedGet(ctx, "associations", path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Media retrieves datasheets, photos and other media for a product.
+func (c *Client) Media(ctx context.Context, productNumber string) (*MediaResponse, error) {
+	if productNumber == "" {
+		return nil, fmt.Errorf("%w: product number is required", ErrInvalidRequest)
+	}
+
+	path := fmt.Sprintf("%s/%s/media", searchBasePath, url.PathEscape(productNumber))
+
+	var resp MediaResponse
+	if err := c.cachedGet(ctx, "media", path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ProductPricingOptions configures a product pricing request.
+type ProductPricingOptions struct {
+	Limit              int  // Maximum number of products (1-10)
+	Offset             int  // Starting position for results
+	InStock            bool // Only include products in stock
+	ExcludeMarketplace bool // Exclude marketplace products
+}
+
+// ProductPricing retrieves pricing for products matching a product number.
+// opts may be nil.
+func (c *Client) ProductPricing(ctx context.Context, productNumber string, opts *ProductPricingOptions) (*ProductPricingResponse, error) {
+	if productNumber == "" {
+		return nil, fmt.Errorf("%w: product number is required", ErrInvalidRequest)
+	}
+
+	query := url.Values{}
+	if opts != nil {
+		if opts.Limit > 0 {
+			query.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Offset > 0 {
+			query.Set("offset", strconv.Itoa(opts.Offset))
+		}
+		if opts.InStock {
+			query.Set("inStock", "true")
+		}
+		if opts.ExcludeMarketplace {
+			query.Set("excludeMarketplace", "true")
+		}
+	}
+	path := withQuery(fmt.Sprintf("%s/%s/pricing", searchBasePath, url.PathEscape(productNumber)), query)
+
+	var resp ProductPricingResponse
+	if err := c.cachedGet(ctx, "pricing", path, &resp); err != nil {
+		return nil, err
+	}
 	return &resp, nil
 }
 
+// PricingByQuantity retrieves the pricing options for a product at the
+// requested quantity. manufacturerID is optional and may be 0.
+func (c *Client) PricingByQuantity(ctx context.Context, productNumber string, quantity, manufacturerID int) (*PricingOptionsForQuantityResponse, error) {
+	if productNumber == "" {
+		return nil, fmt.Errorf("%w: product number is required", ErrInvalidRequest)
+	}
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidRequest)
+	}
+
+	query := url.Values{}
+	if manufacturerID > 0 {
+		query.Set("manufacturerId", strconv.Itoa(manufacturerID))
+	}
+	path := withQuery(fmt.Sprintf("%s/%s/pricingbyquantity/%d", searchBasePath, url.PathEscape(productNumber), quantity), query)
+
+	var resp PricingOptionsForQuantityResponse
+	if err := c.cachedGet(ctx, "pricing", path, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// cachedGet performs a cached GET request. Reference and pricing data share
+// the product details TTL.
+func (c *Client) cachedGet(ctx context.Context, kind, path string, result interface{}) error {
+	cacheKey := c.environment.cacheKey(cacheKeyForPath(kind, c.getLocale(), path))
+	return c.cachedDo(ctx, http.MethodGet, path, nil, cacheKey, c.cacheConfig.DetailsTTL, result)
+}
+
+// withQuery appends an encoded query string to path if query is non-empty.
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
 // SearchOptions provides a builder pattern for constructing search requests.
+// Invalid arguments are collected and reported by Build and Execute.
 type SearchOptions struct {
-	request SearchRequest
+	request           SearchRequest
+	ownsFilter        bool
+	manufacturerNames []string
+	errs              []error
+}
+
+// knownSearchOptions lists the values accepted by WithSearchOptions.
+var knownSearchOptions = map[string]bool{
+	SearchOptionInStock:          true,
+	SearchOptionNormallyStocking: true,
+	SearchOptionNewProduct:       true,
+	SearchOptionRoHSCompliant:    true,
+	SearchOptionHasDatasheet:     true,
+	SearchOptionHasProductPhoto:  true,
+	SearchOptionHas3DModel:       true,
+	SearchOptionHasCADModel:      true,
+}
+
+// knownSortFields lists the values accepted by SortBy.
+var knownSortFields = map[SortField]bool{
+	SortByNone:                             true,
+	SortByPackaging:                        true,
+	SortByProductStatus:                    true,
+	SortByDigiKeyProductNumber:             true,
+	SortByManufacturerProductNumber:        true,
+	SortByManufacturer:                     true,
+	SortByMinimumQuantity:                  true,
+	SortByQuantityAvailable:                true,
+	SortByPrice:                            true,
+	SortBySupplier:                         true,
+	SortByPriceManufacturerStandardPackage: true,
 }
 
 // NewSearch creates a new search options builder.
@@ -157,18 +380,256 @@ func (s *SearchOptions) Offset(position int) *SearchOptions {
 	return s
 }
 
-// WithFilterOptions sets filter options.
+// WithFilterOptions sets filter options. Later filter methods apply to a copy,
+// so filterRequest itself is not modified.
 func (s *SearchOptions) WithFilterOptions(filterRequest *FilterRequest) *SearchOptions {
 	s.request.FilterOptionsRequest = filterRequest
+	s.ownsFilter = false
+	return s
+}
+
+// FilterByCategory restricts results to the given category IDs.
+func (s *SearchOptions) FilterByCategory(categoryIDs ...int) *SearchOptions {
+	if s.checkIDs("category", categoryIDs, 1) {
+		f := s.filter()
+		f.CategoryFilter = append(f.CategoryFilter, categoryIDs...)
+	}
+	return s
+}
+
+// FilterByManufacturer restricts results to the given manufacturer IDs.
+func (s *SearchOptions) FilterByManufacturer(manufacturerIDs ...int) *SearchOptions {
+	if s.checkIDs("manufacturer", manufacturerIDs, 1) {
+		f := s.filter()
+		f.ManufacturerFilter = append(f.ManufacturerFilter, manufacturerIDs...)
+	}
+	return s
+}
+
+// FilterByManufacturerName restricts results to the named manufacturers.
+// Names, aliases such as "TI" and close spellings are resolved to IDs by
+// Execute using the client's ManufacturerIndex. Build does not resolve names.
+func (s *SearchOptions) FilterByManufacturerName(names ...string) *SearchOptions {
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			s.errs = append(s.errs, fmt.Errorf("%w: manufacturer name is empty", ErrInvalidRequest))
+			return s
+		}
+	}
+	s.manufacturerNames = append(s.manufacturerNames, names...)
+	return s
+}
+
+// FilterByStatus restricts results to the given product status IDs.
+// Status 0 is "Active".
+func (s *SearchOptions) FilterByStatus(statusIDs ...int) *SearchOptions {
+	if s.checkIDs("status", statusIDs, 0) {
+		f := s.filter()
+		f.StatusFilter = append(f.StatusFilter, statusIDs...)
+	}
+	return s
+}
+
+// FilterByPackageType restricts results to the given package type IDs.
+func (s *SearchOptions) FilterByPackageType(packageTypeIDs ...int) *SearchOptions {
+	if s.checkIDs("package type", packageTypeIDs, 1) {
+		f := s.filter()
+		f.PackageTypeFilter = append(f.PackageTypeFilter, packageTypeIDs...)
+	}
+	return s
+}
+
+// FilterByParameter restricts results to products whose parameter matches
+// one of the given value IDs. Repeated calls for the same parameter add values.
+func (s *SearchOptions) FilterByParameter(parameterID int, valueIDs ...string) *SearchOptions {
+	if parameterID <= 0 {
+		s.errs = append(s.errs, fmt.Errorf("%w: parameter ID must be positive, got %d", ErrInvalidRequest, parameterID))
+		return s
+	}
+	if len(valueIDs) == 0 {
+		s.errs = append(s.errs, fmt.Errorf("%w: parameter %d requires at least one value", ErrInvalidRequest, parameterID))
+		return s
+	}
+	for _, v := range valueIDs {
+		if v == "" {
+			s.errs = append(s.errs, fmt.Errorf("%w: parameter %d has an empty value ID", ErrInvalidRequest, parameterID))
+			return s
+		}
+	}
+
+	f := s.filter()
+	for i := range f.ParameterFilterRequest {
+		if f.ParameterFilterRequest[i].ParameterID == parameterID {
+			f.ParameterFilterRequest[i].ValueIDs = append(f.ParameterFilterRequest[i].ValueIDs, valueIDs...)
+			return s
+		}
+	}
+	f.ParameterFilterRequest = append(f.ParameterFilterRequest, ParameterFilterRequest{
+		ParameterID: parameterID,
+		ValueIDs:    append([]string(nil), valueIDs...),
+	})
+	return s
+}
+
+// WithSearchOptions adds search options such as SearchOptionInStock.
+func (s *SearchOptions) WithSearchOptions(options ...string) *SearchOptions {
+	for _, opt := range options {
+		if !knownSearchOptions[opt] {
+			s.errs = append(s.errs, fmt.Errorf("%w: unknown search option %q", ErrInvalidRequest, opt))
+			return s
+		}
+	}
+
+	f := s.filter()
+	for _, opt := range options {
+		if !containsString(f.SearchOptions, opt) {
+			f.SearchOptions = append(f.SearchOptions, opt)
+		}
+	}
 	return s
 }
 
-// Build returns the constructed SearchRequest.
-func (s *SearchOptions) Build() *SearchRequest {
-	return &s.request
+// InStock restricts results to products currently in stock.
+func (s *SearchOptions) InStock() *SearchOptions {
+	return s.WithSearchOptions(SearchOptionInStock)
+}
+
+// RoHSCompliant restricts results to RoHS compliant products.
+func (s *SearchOptions) RoHSCompliant() *SearchOptions {
+	return s.WithSearchOptions(SearchOptionRoHSCompliant)
+}
+
+// NewProducts restricts results to newly introduced products.
+func (s *SearchOptions) NewProducts() *SearchOptions {
+	return s.WithSearchOptions(SearchOptionNewProduct)
 }
 
-// Execute performs the search using the provided client.
+// MinimumQuantity restricts results to products with at least quantity available.
+func (s *SearchOptions) MinimumQuantity(quantity int) *SearchOptions {
+	if quantity < 0 {
+		s.errs = append(s.errs, fmt.Errorf("%w: minimum quantity must not be negative, got %d", ErrInvalidRequest, quantity))
+		return s
+	}
+	s.filter().MinimumQuantityAvailable = quantity
+	return s
+}
+
+// SortBy sorts results by field in the given direction.
+func (s *SearchOptions) SortBy(field SortField, direction SortDirection) *SearchOptions {
+	if !knownSortFields[field] {
+		s.errs = append(s.errs, fmt.Errorf("%w: unknown sort field %q", ErrInvalidRequest, field))
+		return s
+	}
+	if direction != SortAscending && direction != SortDescending {
+		s.errs = append(s.errs, fmt.Errorf("%w: unknown sort direction %q", ErrInvalidRequest, direction))
+		return s
+	}
+
+	s.request.SortOptions = &SortOptions{Field: field, Direction: direction}
+	return s
+}
+
+// Build returns the constructed SearchRequest, or the validation errors
+// collected while building it.
+func (s *SearchOptions) Build() (*SearchRequest, error) {
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return &s.request, nil
+}
+
+// Err returns the validation errors collected so far, joined into one error.
+func (s *SearchOptions) Err() error {
+	return errors.Join(s.errs...)
+}
+
+// Execute validates and performs the search using the provided client.
 func (s *SearchOptions) Execute(ctx context.Context, client *Client) (*SearchResponse, error) {
-	return client.KeywordSearch(ctx, &s.request)
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	req, err := s.resolve(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return client.KeywordSearch(ctx, req)
+}
+
+// resolve returns a copy of the request with manufacturer names resolved to IDs.
+func (s *SearchOptions) resolve(ctx context.Context, client *Client) (*SearchRequest, error) {
+	if len(s.manufacturerNames) == 0 {
+		return &s.request, nil
+	}
+
+	idx, err := client.ManufacturerIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req := s.request
+	filter := req.FilterOptionsRequest.clone()
+	for _, name := range s.manufacturerNames {
+		m, err := idx.Resolve(name)
+		if err != nil {
+			return nil, err
+		}
+		filter.ManufacturerFilter = append(filter.ManufacturerFilter, m.ID)
+	}
+
+	req.FilterOptionsRequest = filter
+	return &req, nil
+}
+
+// filter returns the builder's own FilterRequest, copying one supplied
+// through WithFilterOptions before it is first modified.
+func (s *SearchOptions) filter() *FilterRequest {
+	if !s.ownsFilter {
+		s.request.FilterOptionsRequest = s.request.FilterOptionsRequest.clone()
+		s.ownsFilter = true
+	}
+	return s.request.FilterOptionsRequest
+}
+
+// checkIDs records an error if any ID is below min.
+func (s *SearchOptions) checkIDs(kind string, ids []int, min int) bool {
+	for _, id := range ids {
+		if id < min {
+			s.errs = append(s.errs, fmt.Errorf("%w: %s ID must be at least %d, got %d", ErrInvalidRequest, kind, min, id))
+			return false
+		}
+	}
+	return true
+}
+
+// clone returns a deep copy of f, or an empty FilterRequest if f is nil.
+func (f *FilterRequest) clone() *FilterRequest {
+	c := &FilterRequest{}
+	if f == nil {
+		return c
+	}
+
+	*c = *f
+	c.CategoryFilter = append([]int(nil), f.CategoryFilter...)
+	c.ManufacturerFilter = append([]int(nil), f.ManufacturerFilter...)
+	c.StatusFilter = append([]int(nil), f.StatusFilter...)
+	c.PackageTypeFilter = append([]int(nil), f.PackageTypeFilter...)
+	c.SearchOptions = append([]string(nil), f.SearchOptions...)
+	c.ParameterFilterRequest = make([]ParameterFilterRequest, len(f.ParameterFilterRequest))
+	for i, p := range f.ParameterFilterRequest {
+		c.ParameterFilterRequest[i] = ParameterFilterRequest{
+			ParameterID: p.ParameterID,
+			ValueIDs:    append([]string(nil), p.ValueIDs...),
+		}
+	}
+	return c
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
 }
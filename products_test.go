@@ -3,6 +3,8 @@ package digikey
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"net/http"
 	"testing"
 	"time"
 )
@@ -80,7 +82,10 @@ func TestSearchOptionsChaining(t *testing.T) {
 
 // TestSearchOptionsBuild tests Build method
 func TestSearchOptionsBuild(t *testing.T) {
-	built := NewSearch("diode").Limit(15).Offset(3).Build()
+	built, err := NewSearch("diode").Limit(15).Offset(3).Build()
+	if err != nil {
+		t.Fatalf("Build() returned error: %v", err)
+	}
 	if built == nil {
 		t.Error("Build() returned nil")
 		return
@@ -284,3 +289,254 @@ func TestClientWithCacheConfig(t *testing.T) {
 		t.Error("expected non-nil client")
 	}
 }
+
+// TestProductEndpointPaths tests that each endpoint requests the expected path and query.
+func TestProductEndpointPaths(t *testing.T) {
+	var gotPath, gotQuery string
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("expected GET, got %s", r.Method)
+		}
+		gotPath = r.URL.EscapedPath()
+		gotQuery = r.URL.RawQuery
+		_, _ = w.Write([]byte(`{}`))
+	}, WithoutCache())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name  string
+		call  func() error
+		path  string
+		query string
+	}{
+		{"Manufacturers", func() error { _, err := client.Manufacturers(ctx); return err },
+			"/products/v4/search/manufacturers", ""},
+		{"Categories", func() error { _, err := client.Categories(ctx); return err },
+			"/products/v4/search/categories", ""},
+		{"Category", func() error { _, err := client.Category(ctx, 42); return err },
+			"/products/v4/search/categories/42", ""},
+		{"PackageTypeByQuantity", func() error { _, err := client.PackageTypeByQuantity(ctx, "296-1234-ND", 100, "CT"); return err },
+			"/products/v4/search/packagetypebyquantity/296-1234-ND", "packagingPreference=CT&requestedQuantity=100"},
+		{"DigiReelPricing", func() error { _, err := client.DigiReelPricing(ctx, "296-1234-ND", 250); return err },
+			"/products/v4/search/296-1234-ND/digireelpricing", "requestedQuantity=250"},
+		{"RecommendedProducts", func() error {
+			_, err := client.RecommendedProducts(ctx, "296-1234-ND", &RecommendedProductsOptions{Limit: 5, ExcludeMarketplace: true})
+			return err
+		}, "/products/v4/search/296-1234-ND/recommendedproducts", "excludeMarketPlaceProducts=true&limit=5"},
+		{"Substitutions", func() error { _, err := client.Substitutions(ctx, "296-1234-ND"); return err },
+			"/products/v4/search/296-1234-ND/substitutions", ""},
+		{"Associations", func() error { _, err := client.Associations(ctx, "296-1234-ND"); return err },
+			"/products/v4/search/296-1234-ND/associations", ""},
+		{"Media", func() error { _, err := client.Media(ctx, "296-1234-ND"); return err },
+			"/products/v4/search/296-1234-ND/media", ""},
+		{"ProductPricing", func() error {
+			_, err := client.ProductPricing(ctx, "296-1234-ND", &ProductPricingOptions{Limit: 5, InStock: true})
+			return err
+		}, "/products/v4/search/296-1234-ND/pricing", "inStock=true&limit=5"},
+		{"PricingByQuantity", func() error { _, err := client.PricingByQuantity(ctx, "296-1234-ND", 10, 296); return err },
+			"/products/v4/search/296-1234-ND/pricingbyquantity/10", "manufacturerId=296"},
+	}
+
+	for _, test := range tests {
+		if err := test.call(); err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if gotPath != test.path {
+			t.Errorf("%s: expected path %s, got %s", test.name, test.path, gotPath)
+		}
+		if gotQuery != test.query {
+			t.Errorf("%s: expected query %q, got %q", test.name, test.query, gotQuery)
+		}
+	}
+}
+
+// TestProductEndpointValidation tests argument validation for product endpoints.
+func TestProductEndpointValidation(t *testing.T) {
+	client := NewClient("test-id", "test-secret")
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"Category", func() error { _, err := client.Category(ctx, 0); return err }},
+		{"PackageTypeByQuantity", func() error { _, err := client.PackageTypeByQuantity(ctx, "", 1, ""); return err }},
+		{"PackageTypeByQuantityQuantity", func() error { _, err := client.PackageTypeByQuantity(ctx, "PN", 0, ""); return err }},
+		{"DigiReelPricing", func() error { _, err := client.DigiReelPricing(ctx, "PN", 0); return err }},
+		{"RecommendedProducts", func() error { _, err := client.RecommendedProducts(ctx, "", nil); return err }},
+		{"Substitutions", func() error { _, err := client.Substitutions(ctx, ""); return err }},
+		{"Associations", func() error { _, err := client.Associations(ctx, ""); return err }},
+		{"Media", func() error { _, err := client.Media(ctx, ""); return err }},
+		{"ProductPricing", func() error { _, err := client.ProductPricing(ctx, "", nil); return err }},
+		{"PricingByQuantity", func() error { _, err := client.PricingByQuantity(ctx, "PN", -1, 0); return err }},
+	}
+
+	for _, test := range tests {
+		if err := test.call(); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%s: expected ErrInvalidRequest, got %v", test.name, err)
+		}
+	}
+}
+
+// TestPricingByQuantityResponse tests decoding of a pricing options response.
+func TestPricingByQuantityResponse(t *testing.T) {
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"RequestedProduct": "296-1234-ND",
+			"RequestedQuantity": 10,
+			"ManufacturerPartNumber": "LM358",
+			"PricingOptions": [{
+				"PricingOption": "Exact",
+				"TotalQuantityPriced": 10,
+				"TotalPrice": 4.2,
+				"PricingDetail": [{"PackagingType": "Cut Tape", "DigiKeyProductNumber": "296-1234-1-ND", "UnitPrice": 0.42}]
+			}]
+		}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := client.PricingByQuantity(ctx, "296-1234-ND", 10, 0)
+	if err != nil {
+		t.Fatalf("PricingByQuantity failed: %v", err)
+	}
+	if resp.ManufacturerProductNumber != "LM358" {
+		t.Errorf("expected MPN LM358, got %s", resp.ManufacturerProductNumber)
+	}
+	if len(resp.PricingOptions) != 1 || len(resp.PricingOptions[0].PricingDetail) != 1 {
+		t.Fatalf("expected 1 pricing option with 1 detail, got %+v", resp.PricingOptions)
+	}
+	if resp.PricingOptions[0].PricingDetail[0].UnitPrice != 0.42 {
+		t.Errorf("expected unit price 0.42, got %f", resp.PricingOptions[0].PricingDetail[0].UnitPrice)
+	}
+}
+
+// TestSearchOptionsFilters tests the filter builder methods.
+func TestSearchOptionsFilters(t *testing.T) {
+	req, err := NewSearch("mcu").
+		FilterByCategory(685).
+		FilterByManufacturer(497, 150).
+		FilterByStatus(1).
+		FilterByPackageType(3).
+		FilterByParameter(2043, "411897").
+		FilterByParameter(2043, "411898").
+		FilterByParameter(1989, "34").
+		InStock().
+		RoHSCompliant().
+		WithSearchOptions(SearchOptionInStock, SearchOptionHasDatasheet).
+		MinimumQuantity(100).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() returned error: %v", err)
+	}
+
+	f := req.FilterOptionsRequest
+	if f == nil {
+		t.Fatal("expected FilterOptionsRequest to be set")
+	}
+	if len(f.CategoryFilter) != 1 || f.CategoryFilter[0] != 685 {
+		t.Errorf("unexpected category filter %v", f.CategoryFilter)
+	}
+	if len(f.ManufacturerFilter) != 2 {
+		t.Errorf("unexpected manufacturer filter %v", f.ManufacturerFilter)
+	}
+	if len(f.StatusFilter) != 1 || len(f.PackageTypeFilter) != 1 {
+		t.Errorf("unexpected status/package filters %v %v", f.StatusFilter, f.PackageTypeFilter)
+	}
+	if len(f.ParameterFilterRequest) != 2 {
+		t.Fatalf("expected 2 parameter filters, got %d", len(f.ParameterFilterRequest))
+	}
+	if values := f.ParameterFilterRequest[0].ValueIDs; len(values) != 2 {
+		t.Errorf("expected values for parameter 2043 to be merged, got %v", values)
+	}
+	expectedOptions := []string{SearchOptionInStock, SearchOptionRoHSCompliant, SearchOptionHasDatasheet}
+	if len(f.SearchOptions) != len(expectedOptions) {
+		t.Fatalf("expected search options %v, got %v", expectedOptions, f.SearchOptions)
+	}
+	for i := range expectedOptions {
+		if f.SearchOptions[i] != expectedOptions[i] {
+			t.Errorf("search option %d: expected %s, got %s", i, expectedOptions[i], f.SearchOptions[i])
+		}
+	}
+	if f.MinimumQuantityAvailable != 100 {
+		t.Errorf("expected minimum quantity 100, got %d", f.MinimumQuantityAvailable)
+	}
+}
+
+// TestSearchOptionsFilterCopy tests that builder methods do not modify a caller's filter.
+func TestSearchOptionsFilterCopy(t *testing.T) {
+	filter := &FilterRequest{CategoryFilter: []int{1}}
+	req, err := NewSearch("test").WithFilterOptions(filter).FilterByCategory(2).Build()
+	if err != nil {
+		t.Fatalf("Build() returned error: %v", err)
+	}
+
+	if len(filter.CategoryFilter) != 1 {
+		t.Errorf("caller's filter was modified: %v", filter.CategoryFilter)
+	}
+	if len(req.FilterOptionsRequest.CategoryFilter) != 2 {
+		t.Errorf("expected 2 categories, got %v", req.FilterOptionsRequest.CategoryFilter)
+	}
+
+	// Reusing the slice passed to FilterByParameter must not change the request
+	values := []string{"411897", "411898"}
+	req, err = NewSearch("test").FilterByParameter(2043, values...).Build()
+	if err != nil {
+		t.Fatalf("Build() returned error: %v", err)
+	}
+	values[0] = "changed"
+	if got := req.FilterOptionsRequest.ParameterFilterRequest[0].ValueIDs[0]; got != "411897" {
+		t.Errorf("request changed with the caller's slice: %q", got)
+	}
+}
+
+// TestSearchOptionsValidation tests that invalid arguments are collected.
+func TestSearchOptionsValidation(t *testing.T) {
+	search := NewSearch("test").
+		FilterByCategory(0).
+		FilterByManufacturer(-5).
+		FilterByParameter(2043).
+		WithSearchOptions("InStok").
+		MinimumQuantity(-1)
+
+	_, err := search.Build()
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	for _, fragment := range []string{"category ID", "manufacturer ID", "parameter 2043", `"InStok"`, "minimum quantity"} {
+		if !contains(err.Error(), fragment) {
+			t.Errorf("expected error to mention %s, got %v", fragment, err)
+		}
+	}
+
+	// Execute reports validation errors without calling the API
+	client := NewClient("test-id", "test-secret", WithBaseURL("http://127.0.0.1:0"))
+	if _, err := search.Execute(context.Background(), client); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest from Execute, got %v", err)
+	}
+}
+
+// TestSearchOptionsSortBy tests the SortBy builder method.
+func TestSearchOptionsSortBy(t *testing.T) {
+	req, err := NewSearch("LED").SortBy(SortByPrice, SortDescending).Build()
+	if err != nil {
+		t.Fatalf("Build() returned error: %v", err)
+	}
+	if req.SortOptions == nil {
+		t.Fatal("expected SortOptions to be set")
+	}
+	if req.SortOptions.Field != SortByPrice || req.SortOptions.Direction != SortDescending {
+		t.Errorf("unexpected sort options %+v", req.SortOptions)
+	}
+
+	if _, err := NewSearch("LED").SortBy("Popularity", SortAscending).Build(); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for unknown field, got %v", err)
+	}
+	if _, err := NewSearch("LED").SortBy(SortByPrice, "Up").Build(); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for unknown direction, got %v", err)
+	}
+}
@@ -1,12 +1,46 @@
 package digikey
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"strconv"
 	"sync"
 	"time"
 )
 
-// RateLimiter tracks API usage against Digi-Key's rate limits.
+// Limiter decides whether the client may send another request.
+// Implementations must be safe for concurrent use.
+type Limiter interface {
+	// Allow counts a request if one is allowed now, or returns a
+	// *RateLimitError without counting it.
+	Allow() error
+
+	// Wait blocks until a request is allowed and counts it.
+	Wait(ctx context.Context) error
+
+	// WaitTime returns how long until a request would be allowed.
+	WaitTime() time.Duration
+
+	// Stats returns current usage.
+	Stats() RateLimitStats
+
+	// UpdateFromResponse blocks requests for retryAfterSeconds after
+	// the API responds with 429 Too Many Requests.
+	UpdateFromResponse(retryAfterSeconds int)
+}
+
+// RateLimiter tracks API usage against Digi-Key's rate limits using fixed
+// windows. Minute windows start when the limiter is created; the day window
+// resets at UTC midnight, as Digi-Key's daily quota does.
 // Limits: 120 requests/minute, 1000 requests/day.
+//
+// Fixed windows allow up to twice the minute limit across a window boundary.
+// Use a SlidingWindowLimiter to stay strictly within the limit.
 type RateLimiter struct {
 	mu sync.Mutex
 
@@ -21,6 +55,13 @@ type RateLimiter struct {
 	// Limits
 	minuteLimit int
 	dayLimit    int
+
+	// statePath is where state is saved after each change, if set
+	statePath string
+	dirty     bool          // State changed since the last write began
+	saveDone  chan struct{} // Closed when the running writer finishes; nil if none
+
+	queue waitQueue
 }
 
 // NewRateLimiter creates a new rate limiter with default Digi-Key limits.
@@ -30,7 +71,7 @@ func NewRateLimiter() *RateLimiter {
 		minuteLimit:     120,
 		dayLimit:        1000,
 		minuteResetTime: now.Add(time.Minute),
-		dayResetTime:    now.Add(24 * time.Hour),
+		dayResetTime:    nextUTCMidnight(now),
 	}
 }
 
@@ -41,7 +82,7 @@ func NewRateLimiterWithLimits(minuteLimit, dayLimit int) *RateLimiter {
 		minuteLimit:     minuteLimit,
 		dayLimit:        dayLimit,
 		minuteResetTime: now.Add(time.Minute),
-		dayResetTime:    now.Add(24 * time.Hour),
+		dayResetTime:    nextUTCMidnight(now),
 	}
 }
 
@@ -52,18 +93,7 @@ func (r *RateLimiter) Allow() error {
 	defer r.mu.Unlock()
 
 	now := time.Now()
-
-	// Reset minute counter if window has passed
-	if now.After(r.minuteResetTime) {
-		r.minuteCount = 0
-		r.minuteResetTime = now.Add(time.Minute)
-	}
-
-	// Reset day counter if window has passed
-	if now.After(r.dayResetTime) {
-		r.dayCount = 0
-		r.dayResetTime = now.Add(24 * time.Hour)
-	}
+	r.resetWindows(now)
 
 	// Check minute limit
 	if r.minuteCount >= r.minuteLimit {
@@ -88,10 +118,34 @@ func (r *RateLimiter) Allow() error {
 	// Increment counters
 	r.minuteCount++
 	r.dayCount++
+	r.save()
 
 	return nil
 }
 
+// resetWindows starts new windows for any that have passed.
+// The caller must hold r.mu.
+func (r *RateLimiter) resetWindows(now time.Time) {
+	if now.After(r.minuteResetTime) {
+		r.minuteCount = 0
+		r.minuteResetTime = now.Add(time.Minute)
+	}
+	if now.After(r.dayResetTime) {
+		r.dayCount = 0
+		r.dayResetTime = nextUTCMidnight(now)
+	}
+}
+
+// Wait blocks until a request is allowed, then counts it like Allow.
+// Concurrent callers are admitted in the order they called Wait.
+//
+// Wait returns the context's error if it is canceled while waiting, or a
+// *RateLimitError without waiting if the next slot opens after the
+// context's deadline.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	return r.queue.wait(ctx, r.Allow, r.WaitTime)
+}
+
 // Stats returns current rate limit statistics.
 func (r *RateLimiter) Stats() RateLimitStats {
 	r.mu.Lock()
@@ -132,6 +186,9 @@ type RateLimitStats struct {
 	DayUsed         int
 	DayRemaining    int
 	DayResetAt      time.Time
+
+	// Priorities holds per-class counts when using a PriorityLimiter
+	Priorities map[Priority]PriorityStats
 }
 
 // WaitTime returns how long to wait before the next request is allowed.
@@ -188,4 +245,299 @@ func (r *RateLimiter) UpdateFromResponse(retryAfterSeconds int) {
 	// Set minute count to limit to prevent further requests
 	r.minuteCount = r.minuteLimit
 	r.minuteResetTime = time.Now().Add(time.Duration(retryAfterSeconds) * time.Second)
+	r.save()
+}
+
+// ServerRateLimit is the usage Digi-Key reports in response headers.
+// Limits and remaining counts are -1 when their header was absent.
+type ServerRateLimit struct {
+	DayLimit        int           // X-RateLimit-Limit
+	DayRemaining    int           // X-RateLimit-Remaining
+	MinuteLimit     int           // X-BurstLimit-Limit
+	MinuteRemaining int           // X-BurstLimit-Remaining
+	MinuteReset     time.Duration // X-BurstLimit-Reset, 0 if absent
+}
+
+// RateLimitSyncer is implemented by limiters that can adopt the usage
+// reported in Digi-Key response headers. The client calls SyncFromServer
+// after every response that carries rate limit headers. RateLimiter
+// implements it and PriorityLimiter forwards it to the limiter it wraps;
+// SharedLimiter, SlidingWindowLimiter and TokenBucketLimiter ignore the
+// headers.
+type RateLimitSyncer interface {
+	SyncFromServer(limit ServerRateLimit)
+}
+
+// SyncFromServer updates the limits and counts from the server's view. The
+// server is the source of truth: its counts replace the local ones, so room
+// the server reports is usable at once, and a minute reset that differs from
+// the local one starts the window the server is in.
+func (r *RateLimiter) SyncFromServer(limit ServerRateLimit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.resetWindows(now)
+
+	if limit.DayLimit > 0 {
+		r.dayLimit = limit.DayLimit
+	}
+	if limit.DayRemaining >= 0 {
+		r.dayCount = max(0, r.dayLimit-limit.DayRemaining)
+	}
+
+	if limit.MinuteLimit > 0 {
+		r.minuteLimit = limit.MinuteLimit
+	}
+	if limit.MinuteReset > 0 {
+		if reset := now.Add(limit.MinuteReset); absDuration(reset.Sub(r.minuteResetTime)) > serverResetTolerance {
+			r.minuteResetTime = reset
+			r.minuteCount = 0
+		}
+	}
+	if limit.MinuteRemaining >= 0 {
+		r.minuteCount = max(0, r.minuteLimit-limit.MinuteRemaining)
+	}
+
+	r.save()
+}
+
+// serverResetTolerance is how far the server's minute reset may be from the
+// local one before it is treated as a different window. It absorbs network
+// latency and the header's rounding.
+const serverResetTolerance = time.Second
+
+// absDuration returns the absolute value of d.
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// parseRateLimitHeaders reads Digi-Key's rate limit headers. It reports
+// false if none are present.
+func parseRateLimitHeaders(h http.Header) (ServerRateLimit, bool) {
+	limit := ServerRateLimit{
+		DayLimit:        parseHeaderInt(h, "X-RateLimit-Limit"),
+		DayRemaining:    parseHeaderInt(h, "X-RateLimit-Remaining"),
+		MinuteLimit:     parseHeaderInt(h, "X-BurstLimit-Limit"),
+		MinuteRemaining: parseHeaderInt(h, "X-BurstLimit-Remaining"),
+	}
+	if reset, err := strconv.ParseFloat(h.Get("X-BurstLimit-Reset"), 64); err == nil && reset > 0 {
+		limit.MinuteReset = time.Duration(reset * float64(time.Second))
+	}
+
+	found := limit.DayLimit >= 0 || limit.DayRemaining >= 0 ||
+		limit.MinuteLimit >= 0 || limit.MinuteRemaining >= 0 || limit.MinuteReset > 0
+	return limit, found
+}
+
+// parseHeaderInt parses a non-negative integer header, returning -1 if it
+// is absent or invalid.
+func parseHeaderInt(h http.Header, name string) int {
+	n, err := strconv.Atoi(h.Get(name))
+	if err != nil || n < 0 {
+		return -1
+	}
+	return n
+}
+
+// RateLimiterState is a snapshot of a RateLimiter's counters, for carrying
+// usage over a restart.
+type RateLimiterState struct {
+	MinuteCount   int       `json:"minute_count"`
+	MinuteResetAt time.Time `json:"minute_reset_at"`
+	DayCount      int       `json:"day_count"`
+	DayResetAt    time.Time `json:"day_reset_at"`
+}
+
+// Snapshot returns the current counters.
+func (r *RateLimiter) Snapshot() RateLimiterState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.snapshot()
+}
+
+// Restore loads counters from a snapshot. Windows that have ended since the
+// snapshot was taken are ignored, so a snapshot from yesterday does not
+// count against today's quota.
+func (r *RateLimiter) Restore(state RateLimiterState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.restore(state)
+}
+
+// PersistTo restores the state saved at path, if the file exists, and saves
+// the state there after every counted request. Saving happens in the
+// background and is best effort: a failed write does not fail the request.
+// Call Flush before exiting so the last requests are recorded.
+func (r *RateLimiter) PersistTo(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(data) > 0 {
+		var state RateLimiterState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("reading %s: %w", path, err)
+		}
+		r.restore(state)
+	}
+	r.statePath = path
+	return nil
+}
+
+func (r *RateLimiter) snapshot() RateLimiterState {
+	return RateLimiterState{
+		MinuteCount:   r.minuteCount,
+		MinuteResetAt: r.minuteResetTime,
+		DayCount:      r.dayCount,
+		DayResetAt:    r.dayResetTime,
+	}
+}
+
+func (r *RateLimiter) restore(state RateLimiterState) {
+	now := time.Now()
+	if now.Before(state.MinuteResetAt) {
+		r.minuteCount = state.MinuteCount
+		r.minuteResetTime = state.MinuteResetAt
+	}
+	if now.Before(state.DayResetAt) {
+		r.dayCount = state.DayCount
+		r.dayResetTime = state.DayResetAt
+	}
+}
+
+// save schedules a write of the state to statePath, if set. Writes happen
+// outside r.mu in a background goroutine, one at a time, and rapid changes
+// are coalesced into the next write. The caller must hold r.mu.
+func (r *RateLimiter) save() {
+	if r.statePath == "" {
+		return
+	}
+	r.dirty = true
+	if r.saveDone != nil {
+		return
+	}
+	done := make(chan struct{})
+	r.saveDone = done
+	go r.writeState(done)
+}
+
+// writeState writes the state until no changes are pending, then closes done.
+func (r *RateLimiter) writeState(done chan struct{}) {
+	defer close(done)
+	for {
+		r.mu.Lock()
+		if !r.dirty {
+			r.saveDone = nil
+			r.mu.Unlock()
+			return
+		}
+		r.dirty = false
+		path := r.statePath
+		data, err := json.Marshal(r.snapshot())
+		r.mu.Unlock()
+
+		if err == nil {
+			_ = writeFileAtomic(path, data)
+		}
+	}
+}
+
+// Flush waits until the state saved by PersistTo has been written.
+func (r *RateLimiter) Flush() {
+	r.mu.Lock()
+	done := r.saveDone
+	r.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+}
+
+// nextUTCMidnight returns the first UTC midnight after t.
+func nextUTCMidnight(t time.Time) time.Time {
+	utc := t.UTC()
+	return time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+}
+
+// waitQueue admits goroutines waiting for a rate limiter in FIFO order.
+type waitQueue struct {
+	mu      sync.Mutex
+	waiters []chan struct{}
+}
+
+// wait queues the caller, then repeatedly calls allow once at the head of
+// the queue, sleeping for waitTime between attempts. Errors other than a
+// *RateLimitError are returned immediately.
+func (q *waitQueue) wait(ctx context.Context, allow func() error, waitTime func() time.Duration) error {
+	turn := q.enter()
+	defer q.leave(turn)
+
+	select {
+	case <-turn:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	for {
+		err := allow()
+		if err == nil {
+			return nil
+		}
+		var rateErr *RateLimitError
+		if !errors.As(err, &rateErr) {
+			return err
+		}
+
+		wait := waitTime()
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return err
+		}
+		if wait <= 0 {
+			wait = time.Millisecond
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// enter adds a waiter and returns a channel that is closed when it reaches
+// the head of the queue.
+func (q *waitQueue) enter() chan struct{} {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	turn := make(chan struct{})
+	q.waiters = append(q.waiters, turn)
+	if len(q.waiters) == 1 {
+		close(turn)
+	}
+	return turn
+}
+
+// leave removes a waiter, admitting the next one if it was at the head.
+func (q *waitQueue) leave(turn chan struct{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for i, w := range q.waiters {
+		if w != turn {
+			continue
+		}
+		q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+		if i == 0 && len(q.waiters) > 0 {
+			close(q.waiters[0])
+		}
+		return
+	}
 }
@@ -0,0 +1,277 @@
+package digikey
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultStoreTimeout bounds each call a SharedLimiter makes to its store.
+const defaultStoreTimeout = 5 * time.Second
+
+// RateLimitStore holds request counters shared by several clients, so that
+// processes using the same Digi-Key credentials draw from one budget.
+// Implementations must be safe for concurrent use.
+type RateLimitStore interface {
+	// Increment adds delta to the counter for key and returns the new value.
+	// A missing or expired counter starts from zero and expires after ttl.
+	Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+
+	// Get returns the counter for key, or 0 if it is missing or expired.
+	Get(ctx context.Context, key string) (int64, error)
+}
+
+// SharedLimiter enforces minute and day limits using counters in a
+// RateLimitStore. Windows are aligned to UTC, so every client sharing the
+// store agrees on when the minute and day budgets reset. It does not
+// implement RateLimitSyncer: the store's counters are the shared budget, and
+// Digi-Key's rate limit headers are ignored.
+//
+//	store := digikey.NewFileRateLimitStore("/var/lib/myapp/digikey-limits.json")
+//	limiter := digikey.NewSharedLimiter(store, clientID, 120, 1000)
+//	client := digikey.NewClient(clientID, clientSecret, digikey.WithRateLimiter(limiter))
+type SharedLimiter struct {
+	store       RateLimitStore
+	key         string
+	minuteLimit int
+	dayLimit    int
+	timeout     time.Duration
+
+	mu           sync.Mutex
+	blockedUntil time.Time
+
+	queue waitQueue
+}
+
+// NewSharedLimiter creates a limiter whose counters are stored under key,
+// typically the client ID. Clients that should share a budget must use the
+// same store and key.
+func NewSharedLimiter(store RateLimitStore, key string, minuteLimit, dayLimit int) *SharedLimiter {
+	return &SharedLimiter{
+		store:       store,
+		key:         key,
+		minuteLimit: minuteLimit,
+		dayLimit:    dayLimit,
+		timeout:     defaultStoreTimeout,
+	}
+}
+
+// Allow counts a request in the shared store if both budgets have room.
+// Store failures are returned as errors rather than allowing the request.
+func (l *SharedLimiter) Allow() error {
+	now := time.Now()
+	if blocked := l.blocked(now); !blocked.IsZero() {
+		return newRateLimitError(l.minuteLimit, blocked, "minute")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	minuteKey, minuteReset := l.minuteWindow(now)
+	dayKey, dayReset := l.dayWindow(now)
+
+	minute, err := l.store.Increment(ctx, minuteKey, 1, minuteReset.Sub(now))
+	if err != nil {
+		return fmt.Errorf("digikey: rate limit store: %w", err)
+	}
+	if minute > int64(l.minuteLimit) {
+		l.release(ctx, minuteKey, minuteReset.Sub(now))
+		return newRateLimitError(l.minuteLimit, minuteReset, "minute")
+	}
+
+	day, err := l.store.Increment(ctx, dayKey, 1, dayReset.Sub(now))
+	if err != nil {
+		l.release(ctx, minuteKey, minuteReset.Sub(now))
+		return fmt.Errorf("digikey: rate limit store: %w", err)
+	}
+	if day > int64(l.dayLimit) {
+		l.release(ctx, dayKey, dayReset.Sub(now))
+		l.release(ctx, minuteKey, minuteReset.Sub(now))
+		return newRateLimitError(l.dayLimit, dayReset, "day")
+	}
+
+	return nil
+}
+
+// Wait blocks until a request is allowed, then counts it like Allow.
+// Callers in this process are admitted in the order they called Wait.
+func (l *SharedLimiter) Wait(ctx context.Context) error {
+	return l.queue.wait(ctx, l.Allow, l.WaitTime)
+}
+
+// WaitTime returns how long until the shared budgets have room.
+// It returns 0 if the store cannot be read.
+func (l *SharedLimiter) WaitTime() time.Duration {
+	stats, err := l.stats()
+	if err != nil {
+		return 0
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	if stats.MinuteRemaining <= 0 {
+		wait = stats.MinuteResetAt.Sub(now)
+	}
+	if stats.DayRemaining <= 0 {
+		wait = max(wait, stats.DayResetAt.Sub(now))
+	}
+	return max(wait, 0)
+}
+
+// Stats returns usage across all clients sharing the store. If the store
+// cannot be read, only the limits and reset times are filled in.
+func (l *SharedLimiter) Stats() RateLimitStats {
+	stats, _ := l.stats()
+	return stats
+}
+
+// UpdateFromResponse blocks this limiter for the Retry-After period of a 429
+// response and uses up the shared minute budget so other clients back off too.
+func (l *SharedLimiter) UpdateFromResponse(retryAfterSeconds int) {
+	if retryAfterSeconds <= 0 {
+		return
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	l.blockedUntil = now.Add(time.Duration(retryAfterSeconds) * time.Second)
+	l.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	minuteKey, minuteReset := l.minuteWindow(now)
+	used, err := l.store.Get(ctx, minuteKey)
+	if err == nil && used < int64(l.minuteLimit) {
+		_, _ = l.store.Increment(ctx, minuteKey, int64(l.minuteLimit)-used, minuteReset.Sub(now))
+	}
+}
+
+// stats reads the current counters from the store.
+func (l *SharedLimiter) stats() (RateLimitStats, error) {
+	now := time.Now()
+	minuteKey, minuteReset := l.minuteWindow(now)
+	dayKey, dayReset := l.dayWindow(now)
+
+	stats := RateLimitStats{
+		MinuteLimit:     l.minuteLimit,
+		MinuteRemaining: l.minuteLimit,
+		MinuteResetAt:   minuteReset,
+		DayLimit:        l.dayLimit,
+		DayRemaining:    l.dayLimit,
+		DayResetAt:      dayReset,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout)
+	defer cancel()
+
+	minute, err := l.store.Get(ctx, minuteKey)
+	if err != nil {
+		return stats, err
+	}
+	day, err := l.store.Get(ctx, dayKey)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.MinuteUsed = min(int(minute), l.minuteLimit)
+	stats.DayUsed = min(int(day), l.dayLimit)
+	if blocked := l.blocked(now); !blocked.IsZero() {
+		stats.MinuteUsed = l.minuteLimit
+		stats.MinuteResetAt = blocked
+	}
+	stats.MinuteRemaining = l.minuteLimit - stats.MinuteUsed
+	stats.DayRemaining = l.dayLimit - stats.DayUsed
+	return stats, nil
+}
+
+// blocked returns when a Retry-After block ends, or the zero time if
+// requests are not blocked.
+func (l *SharedLimiter) blocked(now time.Time) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil
+	}
+	return time.Time{}
+}
+
+// release gives back a request counted by a rejected Allow.
+func (l *SharedLimiter) release(ctx context.Context, key string, ttl time.Duration) {
+	_, _ = l.store.Increment(ctx, key, -1, ttl)
+}
+
+// minuteWindow returns the counter key and end of the UTC minute containing now.
+func (l *SharedLimiter) minuteWindow(now time.Time) (string, time.Time) {
+	start := now.UTC().Truncate(time.Minute)
+	return l.key + ":minute:" + strconv.FormatInt(start.Unix(), 10), start.Add(time.Minute)
+}
+
+// dayWindow returns the counter key and end of the UTC day containing now.
+func (l *SharedLimiter) dayWindow(now time.Time) (string, time.Time) {
+	end := nextUTCMidnight(now)
+	return l.key + ":day:" + end.AddDate(0, 0, -1).Format("20060102"), end
+}
+
+// MemoryRateLimitStore is a RateLimitStore for clients in one process.
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	counters map[string]storedCounter
+}
+
+// storedCounter is a counter value and its expiry.
+type storedCounter struct {
+	Value     int64     `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewMemoryRateLimitStore creates an in-memory rate limit store.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{counters: make(map[string]storedCounter)}
+}
+
+// Increment adds delta to the counter for key.
+func (s *MemoryRateLimitStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	pruneCounters(s.counters, now)
+	return incrementCounter(s.counters, key, delta, ttl, now), nil
+}
+
+// Get returns the counter for key.
+func (s *MemoryRateLimitStore) Get(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.counters[key]
+	if !ok || !time.Now().Before(c.ExpiresAt) {
+		return 0, nil
+	}
+	return c.Value, nil
+}
+
+// incrementCounter adds delta to a live counter in counters, or starts a new
+// one that expires after ttl.
+func incrementCounter(counters map[string]storedCounter, key string, delta int64, ttl time.Duration, now time.Time) int64 {
+	c, ok := counters[key]
+	if !ok || !now.Before(c.ExpiresAt) {
+		c = storedCounter{ExpiresAt: now.Add(ttl)}
+	}
+	c.Value += delta
+	counters[key] = c
+	return c.Value
+}
+
+// pruneCounters removes expired counters.
+func pruneCounters(counters map[string]storedCounter, now time.Time) {
+	for key, c := range counters {
+		if !now.Before(c.ExpiresAt) {
+			delete(counters, key)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package digikey
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testRateLimitStore checks the RateLimitStore contract against store.
+func testRateLimitStore(t *testing.T, store RateLimitStore) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if v, err := store.Get(ctx, "missing"); err != nil || v != 0 {
+		t.Errorf("Get(missing) = %d, %v; want 0, nil", v, err)
+	}
+
+	for want := int64(1); want <= 3; want++ {
+		v, err := store.Increment(ctx, "counter", 1, time.Minute)
+		if err != nil {
+			t.Fatalf("Increment: %v", err)
+		}
+		if v != want {
+			t.Errorf("Increment = %d, want %d", v, want)
+		}
+	}
+	if v, err := store.Increment(ctx, "counter", -2, time.Minute); err != nil || v != 1 {
+		t.Errorf("Increment(-2) = %d, %v; want 1, nil", v, err)
+	}
+	if v, err := store.Get(ctx, "counter"); err != nil || v != 1 {
+		t.Errorf("Get = %d, %v; want 1, nil", v, err)
+	}
+
+	// Counters expire and restart from zero
+	if _, err := store.Increment(ctx, "short", 5, 20*time.Millisecond); err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if v, err := store.Get(ctx, "short"); err != nil || v != 0 {
+		t.Errorf("Get(expired) = %d, %v; want 0, nil", v, err)
+	}
+	if v, err := store.Increment(ctx, "short", 1, time.Minute); err != nil || v != 1 {
+		t.Errorf("Increment(expired) = %d, %v; want 1, nil", v, err)
+	}
+
+	// Concurrent increments are not lost
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := store.Increment(ctx, "concurrent", 1, time.Minute); err != nil {
+				t.Errorf("Increment: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if v, err := store.Get(ctx, "concurrent"); err != nil || v != 10 {
+		t.Errorf("Get(concurrent) = %d, %v; want 10, nil", v, err)
+	}
+}
+
+// TestMemoryRateLimitStore tests the in-memory store.
+func TestMemoryRateLimitStore(t *testing.T) {
+	testRateLimitStore(t, NewMemoryRateLimitStore())
+}
+
+// TestSharedLimiter tests that limiters sharing a store share one budget.
+func TestSharedLimiter(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	a := NewSharedLimiter(store, "client", 3, 1000)
+	b := NewSharedLimiter(store, "client", 3, 1000)
+	other := NewSharedLimiter(store, "other-client", 3, 1000)
+
+	if minuteLeft(t) < time.Second {
+		time.Sleep(minuteLeft(t) + 10*time.Millisecond)
+	}
+
+	for i, l := range []*SharedLimiter{a, b, a} {
+		if err := l.Allow(); err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+	}
+
+	err := b.Allow()
+	var rle *RateLimitError
+	if !errors.As(err, &rle) || rle.Type != "minute" {
+		t.Fatalf("expected minute RateLimitError, got %v", err)
+	}
+	if err := other.Allow(); err != nil {
+		t.Errorf("a different key should have its own budget, got %v", err)
+	}
+
+	stats := a.Stats()
+	if stats.MinuteUsed != 3 || stats.MinuteRemaining != 0 || stats.DayUsed != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.MinuteResetAt.Second() != 0 || stats.DayResetAt.Hour() != 0 || stats.DayResetAt.Location() != time.UTC {
+		t.Errorf("expected UTC-aligned reset times, got %v and %v", stats.MinuteResetAt, stats.DayResetAt)
+	}
+	if wait := a.WaitTime(); wait <= 0 || wait > time.Minute {
+		t.Errorf("expected wait until the next minute, got %v", wait)
+	}
+}
+
+// TestSharedLimiterDayLimit tests that rejected requests are not counted.
+func TestSharedLimiterDayLimit(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	l := NewSharedLimiter(store, "client", 100, 2)
+
+	_ = l.Allow()
+	_ = l.Allow()
+
+	for i := 0; i < 3; i++ {
+		err := l.Allow()
+		var rle *RateLimitError
+		if !errors.As(err, &rle) || rle.Type != "day" {
+			t.Fatalf("expected day RateLimitError, got %v", err)
+		}
+	}
+
+	stats := l.Stats()
+	if stats.DayUsed != 2 || stats.MinuteUsed != 2 {
+		t.Errorf("rejected requests should not be counted, got %+v", stats)
+	}
+}
+
+// TestSharedLimiterUpdateFromResponse tests that a 429 stops other clients.
+func TestSharedLimiterUpdateFromResponse(t *testing.T) {
+	store := NewMemoryRateLimitStore()
+	a := NewSharedLimiter(store, "client", 100, 1000)
+	b := NewSharedLimiter(store, "client", 100, 1000)
+
+	if minuteLeft(t) < time.Second {
+		time.Sleep(minuteLeft(t) + 10*time.Millisecond)
+	}
+
+	a.UpdateFromResponse(120)
+
+	if err := a.Allow(); err == nil {
+		t.Error("expected limiter to be blocked after Retry-After")
+	}
+	if wait := a.WaitTime(); wait < 119*time.Second {
+		t.Errorf("expected ~120s wait, got %v", wait)
+	}
+	if err := b.Allow(); err == nil {
+		t.Error("expected shared minute budget to be used up")
+	}
+}
+
+// failingStore is a RateLimitStore that always fails.
+type failingStore struct{}
+
+func (failingStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	return 0, errors.New("store unavailable")
+}
+
+func (failingStore) Get(ctx context.Context, key string) (int64, error) {
+	return 0, errors.New("store unavailable")
+}
+
+// TestSharedLimiterStoreError tests that store failures are reported.
+func TestSharedLimiterStoreError(t *testing.T) {
+	l := NewSharedLimiter(failingStore{}, "client", 100, 1000)
+
+	err := l.Allow()
+	if err == nil || !strings.Contains(err.Error(), "store unavailable") {
+		t.Errorf("expected store error, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := l.Wait(ctx); err == nil || errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected Wait to return the store error immediately, got %v", err)
+	}
+
+	if stats := l.Stats(); stats.MinuteLimit != 100 || stats.MinuteRemaining != 100 {
+		t.Errorf("expected limits in stats, got %+v", stats)
+	}
+}
+
+// minuteLeft returns the time left in the current UTC minute.
+func minuteLeft(t *testing.T) time.Duration {
+	t.Helper()
+	now := time.Now()
+	return now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+}
@@ -0,0 +1,234 @@
+package digikey
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// fileLockPoll is how often a held lock file is retried.
+	fileLockPoll = 5 * time.Millisecond
+
+	// fileLockStale is how old a lock file must be before it is assumed to
+	// belong to a crashed process and removed.
+	fileLockStale = 30 * time.Second
+
+	// fileLockRefresh is how often a held lock file's modification time is
+	// updated, so locks held longer than fileLockStale are not taken over.
+	fileLockRefresh = fileLockStale / 3
+)
+
+// FileRateLimitStore is a RateLimitStore kept in a JSON file, for processes
+// on one host. Updates are serialized with a lock file created next to it.
+type FileRateLimitStore struct {
+	path string
+}
+
+// NewFileRateLimitStore creates a store backed by the file at path.
+// The file and its lock are created when first needed.
+func NewFileRateLimitStore(path string) *FileRateLimitStore {
+	return &FileRateLimitStore{path: path}
+}
+
+// Increment adds delta to the counter for key.
+func (s *FileRateLimitStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	var value int64
+	err := s.update(ctx, func(counters map[string]storedCounter, now time.Time) bool {
+		value = incrementCounter(counters, key, delta, ttl, now)
+		return true
+	})
+	return value, err
+}
+
+// Get returns the counter for key.
+func (s *FileRateLimitStore) Get(ctx context.Context, key string) (int64, error) {
+	var value int64
+	err := s.update(ctx, func(counters map[string]storedCounter, now time.Time) bool {
+		if c, ok := counters[key]; ok && now.Before(c.ExpiresAt) {
+			value = c.Value
+		}
+		return false
+	})
+	return value, err
+}
+
+// update runs fn on the stored counters while holding the lock, writing
+// them back if fn returns true.
+func (s *FileRateLimitStore) update(ctx context.Context, fn func(counters map[string]storedCounter, now time.Time) bool) error {
+	unlock, err := s.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	counters := make(map[string]storedCounter)
+	data, err := os.ReadFile(s.path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &counters); err != nil {
+			return fmt.Errorf("reading %s: %w", s.path, err)
+		}
+	}
+
+	now := time.Now()
+	if !fn(counters, now) {
+		return nil
+	}
+	pruneCounters(counters, now)
+
+	data, err = json.Marshal(counters)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// lock acquires the lock file, waiting while another process holds it.
+func (s *FileRateLimitStore) lock(ctx context.Context) (func(), error) {
+	return lockFile(ctx, s.path+".lock")
+}
+
+// lockFile creates lockPath exclusively, waiting while another process
+// holds it. The lock file records a random owner and is kept fresh while
+// held. The returned function releases the lock, unless another process
+// has taken it over in the meantime.
+func lockFile(ctx context.Context, lockPath string) (func(), error) {
+	owner := make([]byte, 16)
+	if _, err := rand.Read(owner); err != nil {
+		return nil, err
+	}
+	owner = []byte(hex.EncodeToString(owner))
+
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, err := f.Write(owner)
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = os.Remove(lockPath)
+				return nil, err
+			}
+			return holdLockFile(lockPath, owner), nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > fileLockStale {
+			removed, err := breakStaleLock(lockPath)
+			if err != nil {
+				return nil, err
+			}
+			if removed {
+				continue
+			}
+		}
+		if err := sleep(ctx, fileLockPoll); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// breakStaleLock removes the lock file at lockPath if it is stale and
+// reports whether it did. Waiters take over a stale lock one at a time,
+// holding a takeover file created exclusively next to it, so none removes a
+// lock another waiter has just taken over.
+func breakStaleLock(lockPath string) (bool, error) {
+	takeover := lockPath + ".takeover"
+	f, err := os.OpenFile(takeover, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		// Another waiter is taking over, or crashed doing so
+		if info, err := os.Stat(takeover); err == nil && time.Since(info.ModTime()) > fileLockStale {
+			_ = os.Remove(takeover)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = os.Remove(takeover) }()
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+
+	// A lock taken over before this waiter got here is fresh
+	info, err := os.Stat(lockPath)
+	if err != nil || time.Since(info.ModTime()) <= fileLockStale {
+		return false, nil
+	}
+	if err := os.Remove(lockPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	return true, nil
+}
+
+// holdLockFile updates the modification time of the lock file at lockPath
+// every fileLockRefresh until the returned function is called, which then
+// removes the file if it still belongs to owner.
+func holdLockFile(lockPath string, owner []byte) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(fileLockRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				if !ownsLockFile(lockPath, owner) {
+					return
+				}
+				// A failed update lets the lock go stale, as if the process had crashed
+				_ = os.Chtimes(lockPath, now, now)
+			}
+		}
+	}()
+
+	return func() {
+		close(stop)
+		<-done
+		if ownsLockFile(lockPath, owner) {
+			_ = os.Remove(lockPath)
+		}
+	}
+}
+
+// ownsLockFile reports whether the lock file at lockPath belongs to owner.
+func ownsLockFile(lockPath string, owner []byte) bool {
+	data, err := os.ReadFile(lockPath)
+	return err == nil && bytes.Equal(data, owner)
+}
+
+// writeFileAtomic replaces path with data by renaming a temporary file, so
+// readers never see a partial write.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,117 @@
+package digikey
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestFileRateLimitStore tests the file store.
+func TestFileRateLimitStore(t *testing.T) {
+	testRateLimitStore(t, NewFileRateLimitStore(filepath.Join(t.TempDir(), "limits.json")))
+}
+
+// TestFileRateLimitStoreShared tests that stores on the same file share counters.
+func TestFileRateLimitStoreShared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.json")
+	a := NewFileRateLimitStore(path)
+	b := NewFileRateLimitStore(path)
+
+	ctx := context.Background()
+	if _, err := a.Increment(ctx, "k", 2, time.Minute); err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+	if v, err := b.Increment(ctx, "k", 1, time.Minute); err != nil || v != 3 {
+		t.Errorf("Increment = %d, %v; want 3, nil", v, err)
+	}
+}
+
+// TestFileRateLimitStoreLock tests waiting for and breaking the lock file.
+func TestFileRateLimitStoreLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "limits.json")
+	store := NewFileRateLimitStore(path)
+
+	if err := os.WriteFile(path+".lock", nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := store.Increment(ctx, "k", 1, time.Minute); err != context.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded while lock is held, got %v", err)
+	}
+
+	// A lock left behind by a crashed process is removed
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := store.Increment(context.Background(), "k", 1, time.Minute); err != nil || v != 1 {
+		t.Errorf("Increment = %d, %v; want 1, nil", v, err)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Error("expected lock file to be removed after use")
+	}
+}
+
+// TestLockFileTakenOver tests that releasing a lock taken over by another
+// process leaves the new owner's lock in place.
+func TestLockFileTakenOver(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "tokens.json.refresh.lock")
+
+	unlock, err := lockFile(context.Background(), lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process found the lock stale and replaced it
+	if err := os.WriteFile(lockPath, []byte("other"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+
+	data, err := os.ReadFile(lockPath)
+	if err != nil || string(data) != "other" {
+		t.Errorf("expected the new owner's lock to remain, got %q, %v", data, err)
+	}
+}
+
+// TestLockFileTakeover tests that a stale lock is only taken over by the
+// waiter holding the takeover file.
+func TestLockFileTakeover(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "limits.json.lock")
+	old := time.Now().Add(-time.Hour)
+	for _, path := range []string{lockPath, lockPath + ".takeover"} {
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another waiter is taking over the stale lock
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := lockFile(ctx, lockPath); err != context.DeadlineExceeded {
+		t.Errorf("expected DeadlineExceeded during another takeover, got %v", err)
+	}
+
+	// A takeover file left behind by a crashed waiter is removed
+	if err := os.Chtimes(lockPath+".takeover", old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := lockFile(context.Background(), lockPath)
+	if err != nil {
+		t.Fatalf("lockFile: %v", err)
+	}
+	unlock()
+
+	for _, path := range []string{lockPath, lockPath + ".takeover"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", filepath.Base(path))
+		}
+	}
+}
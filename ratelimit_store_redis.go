@@ -0,0 +1,240 @@
+package digikey
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RedisRateLimitStore is a RateLimitStore kept in Redis or any server
+// speaking the Redis protocol, for clients spread across hosts. It uses a
+// single connection, opened on first use and reopened after network errors.
+type RedisRateLimitStore struct {
+	addr     string
+	password string
+	db       int
+
+	mu   sync.Mutex
+	conn net.Conn
+	rd   *bufio.Reader
+}
+
+// redisError is an error reply from the server.
+type redisError string
+
+func (e redisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// NewRedisRateLimitStore creates a store using the server at addr
+// ("host:port"). password and db select the AUTH password and database
+// number; leave them empty and zero for the defaults.
+func NewRedisRateLimitStore(addr, password string, db int) *RedisRateLimitStore {
+	return &RedisRateLimitStore{addr: addr, password: password, db: db}
+}
+
+// Increment adds delta to the counter for key. The counter is created with
+// its expiry and incremented in one transaction.
+func (s *RedisRateLimitStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	ttlMs := max(ttl.Milliseconds(), 1)
+	replies, err := s.do(ctx,
+		[]string{"MULTI"},
+		[]string{"SET", key, "0", "PX", strconv.FormatInt(ttlMs, 10), "NX"},
+		[]string{"INCRBY", key, strconv.FormatInt(delta, 10)},
+		[]string{"EXEC"},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	results, ok := replies[3].([]interface{})
+	if !ok || len(results) != 2 {
+		return 0, fmt.Errorf("redis: unexpected EXEC reply %v", replies[3])
+	}
+	if err, ok := results[1].(redisError); ok {
+		return 0, err
+	}
+	value, ok := results[1].(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected INCRBY reply %v", results[1])
+	}
+	return value, nil
+}
+
+// Get returns the counter for key.
+func (s *RedisRateLimitStore) Get(ctx context.Context, key string) (int64, error) {
+	replies, err := s.do(ctx, []string{"GET", key})
+	if err != nil {
+		return 0, err
+	}
+
+	switch reply := replies[0].(type) {
+	case nil:
+		return 0, nil
+	case string:
+		return strconv.ParseInt(reply, 10, 64)
+	default:
+		return 0, fmt.Errorf("redis: unexpected GET reply %v", reply)
+	}
+}
+
+// Close closes the connection to the server.
+func (s *RedisRateLimitStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+// do sends cmds as a pipeline and returns one reply per command. An error
+// reply to any command is returned as the error.
+func (s *RedisRateLimitStore) do(ctx context.Context, cmds ...[]string) ([]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	replies, err := s.roundTrip(ctx, cmds)
+	if err != nil {
+		var replyErr redisError
+		if !errors.As(err, &replyErr) {
+			_ = s.conn.Close()
+			s.conn = nil
+		}
+		return nil, err
+	}
+	return replies, nil
+}
+
+// connect dials the server and selects the configured password and database.
+func (s *RedisRateLimitStore) connect(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.conn = conn
+	s.rd = bufio.NewReader(conn)
+
+	var setup [][]string
+	if s.password != "" {
+		setup = append(setup, []string{"AUTH", s.password})
+	}
+	if s.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(s.db)})
+	}
+	if len(setup) == 0 {
+		return nil
+	}
+
+	if _, err := s.roundTrip(ctx, setup); err != nil {
+		_ = conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+// roundTrip writes cmds and reads their replies on the open connection.
+func (s *RedisRateLimitStore) roundTrip(ctx context.Context, cmds [][]string) ([]interface{}, error) {
+	deadline, _ := ctx.Deadline() // The zero time clears any previous deadline
+	if err := s.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	for _, cmd := range cmds {
+		writeRedisCommand(&b, cmd)
+	}
+	if _, err := io.WriteString(s.conn, b.String()); err != nil {
+		return nil, err
+	}
+
+	replies := make([]interface{}, len(cmds))
+	var replyErr error
+	for i := range cmds {
+		reply, err := readRedisReply(s.rd)
+		if err != nil {
+			return nil, err
+		}
+		if err, ok := reply.(redisError); ok && replyErr == nil {
+			replyErr = err
+		}
+		replies[i] = reply
+	}
+	if replyErr != nil {
+		return nil, replyErr
+	}
+	return replies, nil
+}
+
+// writeRedisCommand encodes cmd as a RESP array of bulk strings.
+func writeRedisCommand(b *strings.Builder, cmd []string) {
+	fmt.Fprintf(b, "*%d\r\n", len(cmd))
+	for _, arg := range cmd {
+		fmt.Fprintf(b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+}
+
+// readRedisReply decodes one RESP reply. Simple and bulk strings are
+// returned as string, integers as int64, arrays as []interface{}, nulls as
+// nil, and error replies as redisError.
+func readRedisReply(rd *bufio.Reader) (interface{}, error) {
+	line, err := rd.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return redisError(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(rd, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readRedisReply(rd); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
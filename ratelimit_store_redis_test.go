@@ -0,0 +1,237 @@
+package digikey
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a minimal Redis protocol server supporting the commands used
+// by RedisRateLimitStore.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	values   map[string]int64
+	expires  map[string]time.Time
+	commands []string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &fakeRedis{
+		listener: ln,
+		password: password,
+		values:   make(map[string]int64),
+		expires:  make(map[string]time.Time),
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go r.serve(conn)
+		}
+	}()
+	return r
+}
+
+func (r *fakeRedis) addr() string {
+	return r.listener.Addr().String()
+}
+
+func (r *fakeRedis) serve(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	rd := bufio.NewReader(conn)
+	authed := r.password == ""
+	var queued [][]string
+	inMulti := false
+
+	for {
+		reply, err := readRedisReply(rd)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		cmd := make([]string, len(items))
+		for i, item := range items {
+			cmd[i], _ = item.(string)
+		}
+		if len(cmd) == 0 {
+			return
+		}
+		name := strings.ToUpper(cmd[0])
+
+		r.mu.Lock()
+		r.commands = append(r.commands, name)
+		r.mu.Unlock()
+
+		var out string
+		switch {
+		case name == "AUTH":
+			authed = len(cmd) == 2 && cmd[1] == r.password
+			out = "+OK\r\n"
+			if !authed {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			out = "-NOAUTH Authentication required\r\n"
+		case name == "MULTI":
+			inMulti = true
+			out = "+OK\r\n"
+		case name == "EXEC":
+			out = fmt.Sprintf("*%d\r\n", len(queued))
+			for _, q := range queued {
+				out += r.exec(q)
+			}
+			queued, inMulti = nil, false
+		case inMulti:
+			queued = append(queued, cmd)
+			out = "+QUEUED\r\n"
+		default:
+			out = r.exec(cmd)
+		}
+		if _, err := conn.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+func (r *fakeRedis) exec(cmd []string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := ""
+	if len(cmd) > 1 {
+		key = cmd[1]
+	}
+	if exp, ok := r.expires[key]; ok && !time.Now().Before(exp) {
+		delete(r.values, key)
+		delete(r.expires, key)
+	}
+	_, exists := r.values[key]
+
+	switch strings.ToUpper(cmd[0]) {
+	case "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		if !exists {
+			return "$-1\r\n"
+		}
+		v := strconv.FormatInt(r.values[key], 10)
+		return fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+	case "SET": // SET key value PX ms NX
+		if exists {
+			return "$-1\r\n"
+		}
+		v, _ := strconv.ParseInt(cmd[2], 10, 64)
+		ms, _ := strconv.Atoi(cmd[4])
+		r.values[key] = v
+		r.expires[key] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		return "+OK\r\n"
+	case "INCRBY":
+		delta, _ := strconv.ParseInt(cmd[2], 10, 64)
+		r.values[key] += delta
+		return fmt.Sprintf(":%d\r\n", r.values[key])
+	default:
+		return "-ERR unknown command\r\n"
+	}
+}
+
+// TestRedisRateLimitStore tests the Redis store against a fake server.
+func TestRedisRateLimitStore(t *testing.T) {
+	server := newFakeRedis(t, "")
+	store := NewRedisRateLimitStore(server.addr(), "", 0)
+	defer func() { _ = store.Close() }()
+
+	testRateLimitStore(t, store)
+}
+
+// TestRedisRateLimitStoreAuth tests AUTH and SELECT on connect.
+func TestRedisRateLimitStoreAuth(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	store := NewRedisRateLimitStore(server.addr(), "secret", 2)
+	defer func() { _ = store.Close() }()
+	if v, err := store.Increment(ctx, "k", 1, time.Minute); err != nil || v != 1 {
+		t.Fatalf("Increment = %d, %v; want 1, nil", v, err)
+	}
+
+	server.mu.Lock()
+	if len(server.commands) < 2 || server.commands[0] != "AUTH" || server.commands[1] != "SELECT" {
+		t.Errorf("expected AUTH and SELECT first, got %v", server.commands)
+	}
+	server.mu.Unlock()
+
+	bad := NewRedisRateLimitStore(server.addr(), "wrong", 0)
+	defer func() { _ = bad.Close() }()
+	if _, err := bad.Get(ctx, "k"); err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("expected auth error, got %v", err)
+	}
+}
+
+// TestRedisRateLimitStoreReconnect tests reconnecting after the connection drops.
+func TestRedisRateLimitStoreReconnect(t *testing.T) {
+	server := newFakeRedis(t, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	store := NewRedisRateLimitStore(server.addr(), "", 0)
+	defer func() { _ = store.Close() }()
+	if _, err := store.Increment(ctx, "k", 1, time.Minute); err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+
+	store.mu.Lock()
+	_ = store.conn.Close()
+	store.mu.Unlock()
+
+	// The first call fails on the closed connection, the next reconnects
+	_, _ = store.Get(ctx, "k")
+	if v, err := store.Get(ctx, "k"); err != nil || v != 1 {
+		t.Errorf("Get = %d, %v; want 1, nil", v, err)
+	}
+}
+
+// TestSharedLimiterRedis tests limiters on separate connections sharing a budget.
+func TestSharedLimiterRedis(t *testing.T) {
+	server := newFakeRedis(t, "")
+	storeA := NewRedisRateLimitStore(server.addr(), "", 0)
+	storeB := NewRedisRateLimitStore(server.addr(), "", 0)
+	defer func() { _ = storeA.Close() }()
+	defer func() { _ = storeB.Close() }()
+
+	a := NewSharedLimiter(storeA, "client", 100, 2)
+	b := NewSharedLimiter(storeB, "client", 100, 2)
+
+	if err := a.Allow(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Allow(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Allow(); err == nil {
+		t.Error("expected shared day budget to be used up")
+	}
+	if stats := b.Stats(); stats.DayUsed != 2 {
+		t.Errorf("expected 2 requests counted, got %+v", stats)
+	}
+}
@@ -0,0 +1,90 @@
+package digikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+)
+
+// sqlCleanupInterval is how often a SQLRateLimitStore deletes expired counters.
+const sqlCleanupInterval = time.Minute
+
+// SQLRateLimitStore is a RateLimitStore kept in a database table, for
+// processes that share a database such as SQLite. The database must accept
+// ? placeholders and INSERT ... ON CONFLICT upserts. The caller provides the
+// *sql.DB and its driver.
+type SQLRateLimitStore struct {
+	db *sql.DB
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+// NewSQLRateLimitStore creates a store using db, creating the
+// digikey_rate_limits table if it does not exist.
+func NewSQLRateLimitStore(ctx context.Context, db *sql.DB) (*SQLRateLimitStore, error) {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS digikey_rate_limits (
+	name TEXT PRIMARY KEY,
+	value INTEGER NOT NULL,
+	expires_at INTEGER NOT NULL
+)`)
+	if err != nil {
+		return nil, err
+	}
+	return &SQLRateLimitStore{db: db}, nil
+}
+
+// Increment adds delta to the counter for key.
+func (s *SQLRateLimitStore) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	now := time.Now()
+	s.cleanup(ctx, now)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }() // No-op after Commit
+
+	nowMs := now.UnixMilli()
+	_, err = tx.ExecContext(ctx, `INSERT INTO digikey_rate_limits (name, value, expires_at) VALUES (?, ?, ?)
+ON CONFLICT (name) DO UPDATE SET
+	value = CASE WHEN digikey_rate_limits.expires_at <= ? THEN excluded.value ELSE digikey_rate_limits.value + excluded.value END,
+	expires_at = CASE WHEN digikey_rate_limits.expires_at <= ? THEN excluded.expires_at ELSE digikey_rate_limits.expires_at END`,
+		key, delta, now.Add(ttl).UnixMilli(), nowMs, nowMs)
+	if err != nil {
+		return 0, err
+	}
+
+	var value int64
+	if err := tx.QueryRowContext(ctx, `SELECT value FROM digikey_rate_limits WHERE name = ?`, key).Scan(&value); err != nil {
+		return 0, err
+	}
+	return value, tx.Commit()
+}
+
+// Get returns the counter for key.
+func (s *SQLRateLimitStore) Get(ctx context.Context, key string) (int64, error) {
+	var value int64
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM digikey_rate_limits WHERE name = ? AND expires_at > ?`,
+		key, time.Now().UnixMilli()).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return value, err
+}
+
+// cleanup deletes expired counters, at most once per sqlCleanupInterval.
+// Failures are ignored; expired rows are overwritten when reused anyway.
+func (s *SQLRateLimitStore) cleanup(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.lastCleanup) < sqlCleanupInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastCleanup = now
+	s.mu.Unlock()
+
+	_, _ = s.db.ExecContext(ctx, `DELETE FROM digikey_rate_limits WHERE expires_at <= ?`, now.UnixMilli())
+}
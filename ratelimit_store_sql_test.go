@@ -0,0 +1,182 @@
+package digikey
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSQLDB is an in-memory table for a fake database/sql driver that
+// understands the statements used by SQLRateLimitStore. Transactions are
+// serialized, as in SQLite.
+type fakeSQLDB struct {
+	txMu sync.Mutex
+
+	mu      sync.Mutex
+	created bool
+	rows    map[string][2]int64 // name -> value, expires_at
+}
+
+var registerFakeSQL sync.Once
+
+func openFakeSQL(t *testing.T) (*sql.DB, *fakeSQLDB) {
+	t.Helper()
+
+	registerFakeSQL.Do(func() { sql.Register("digikeyfake", fakeSQLDriver{}) })
+	d := &fakeSQLDB{rows: make(map[string][2]int64)}
+	fakeSQLDBs.Store(t.Name(), d)
+
+	db, err := sql.Open("digikeyfake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db, d
+}
+
+// fakeSQLDBs maps data source names to their fake databases.
+var fakeSQLDBs sync.Map
+
+type fakeSQLDriver struct{}
+
+func (fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	d, ok := fakeSQLDBs.Load(name)
+	if !ok {
+		return nil, errors.New("unknown database")
+	}
+	return &fakeSQLConn{db: d.(*fakeSQLDB)}, nil
+}
+
+type fakeSQLConn struct {
+	db *fakeSQLDB
+}
+
+func (c *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeSQLStmt{db: c.db, query: query}, nil
+}
+
+func (c *fakeSQLConn) Close() error { return nil }
+
+func (c *fakeSQLConn) Begin() (driver.Tx, error) {
+	c.db.txMu.Lock()
+	return fakeSQLTx{db: c.db}, nil
+}
+
+type fakeSQLTx struct{ db *fakeSQLDB }
+
+func (tx fakeSQLTx) Commit() error   { tx.db.txMu.Unlock(); return nil }
+func (tx fakeSQLTx) Rollback() error { tx.db.txMu.Unlock(); return nil }
+
+type fakeSQLStmt struct {
+	db    *fakeSQLDB
+	query string
+}
+
+func (s *fakeSQLStmt) Close() error  { return nil }
+func (s *fakeSQLStmt) NumInput() int { return -1 }
+
+func (s *fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS digikey_rate_limits"):
+		d.created = true
+	case strings.HasPrefix(s.query, "INSERT INTO digikey_rate_limits") && strings.Contains(s.query, "ON CONFLICT (name)"):
+		name, delta, expires, now := args[0].(string), args[1].(int64), args[2].(int64), args[3].(int64)
+		row, ok := d.rows[name]
+		if !ok || row[1] <= now {
+			row = [2]int64{delta, expires}
+		} else {
+			row[0] += delta
+		}
+		d.rows[name] = row
+	case strings.HasPrefix(s.query, "DELETE FROM digikey_rate_limits WHERE expires_at <= ?"):
+		for name, row := range d.rows {
+			if row[1] <= args[0].(int64) {
+				delete(d.rows, name)
+			}
+		}
+	default:
+		return nil, errors.New("unexpected exec: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	d := s.db
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !strings.HasPrefix(s.query, "SELECT value FROM digikey_rate_limits WHERE name = ?") {
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	row, ok := d.rows[args[0].(string)]
+	if ok && len(args) == 2 && row[1] <= args[1].(int64) {
+		ok = false
+	}
+	if !ok {
+		return &fakeSQLRows{}, nil
+	}
+	return &fakeSQLRows{values: []int64{row[0]}}, nil
+}
+
+type fakeSQLRows struct{ values []int64 }
+
+func (r *fakeSQLRows) Columns() []string { return []string{"value"} }
+func (r *fakeSQLRows) Close() error      { return nil }
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+// TestSQLRateLimitStore tests the SQL store against a fake driver.
+func TestSQLRateLimitStore(t *testing.T) {
+	db, fake := openFakeSQL(t)
+
+	store, err := NewSQLRateLimitStore(context.Background(), db)
+	if err != nil {
+		t.Fatalf("NewSQLRateLimitStore: %v", err)
+	}
+	if !fake.created {
+		t.Error("expected table to be created")
+	}
+
+	testRateLimitStore(t, store)
+}
+
+// TestSQLRateLimitStoreCleanup tests that expired rows are deleted.
+func TestSQLRateLimitStoreCleanup(t *testing.T) {
+	db, fake := openFakeSQL(t)
+	store, err := NewSQLRateLimitStore(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	_, _ = store.Increment(ctx, "old", 1, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	store.lastCleanup = time.Time{}
+	_, _ = store.Increment(ctx, "new", 1, time.Minute)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if _, ok := fake.rows["old"]; ok {
+		t.Error("expected expired row to be deleted")
+	}
+	if _, ok := fake.rows["new"]; !ok {
+		t.Error("expected new row to be kept")
+	}
+}
@@ -1,6 +1,13 @@
 package digikey
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 )
@@ -240,3 +247,306 @@ func TestRateLimitErrorDay(t *testing.T) {
 		t.Errorf("error should mention limit: %s", msg)
 	}
 }
+
+// TestRateLimiterWait tests that Wait blocks until the window resets and
+// admits waiters in arrival order.
+func TestRateLimiterWait(t *testing.T) {
+	rl := NewRateLimiterWithLimits(3, 1000)
+	for i := 0; i < 3; i++ {
+		_ = rl.Allow()
+	}
+	rl.mu.Lock()
+	rl.minuteResetTime = time.Now().Add(50 * time.Millisecond)
+	rl.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var (
+		mu    sync.Mutex
+		order []int
+		wg    sync.WaitGroup
+	)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := rl.Wait(ctx); err != nil {
+				t.Errorf("waiter %d: unexpected error: %v", i, err)
+				return
+			}
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		}(i)
+		// Let each waiter join the queue before starting the next
+		for queuedWaiters(rl) <= i {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	wg.Wait()
+
+	if len(order) != 3 || order[0] != 0 || order[1] != 1 || order[2] != 2 {
+		t.Errorf("expected FIFO order [0 1 2], got %v", order)
+	}
+}
+
+// TestRateLimiterWaitDeadline tests that Wait fails fast when the next slot
+// opens after the context deadline.
+func TestRateLimiterWaitDeadline(t *testing.T) {
+	rl := NewRateLimiterWithLimits(1, 1000)
+	_ = rl.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := rl.Wait(ctx)
+	var rle *RateLimitError
+	if !errors.As(err, &rle) {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if time.Since(start) > 50*time.Millisecond {
+		t.Error("Wait should return without sleeping until the deadline")
+	}
+}
+
+// TestRateLimiterWaitCanceled tests that queued waiters stop on cancellation.
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	rl := NewRateLimiterWithLimits(1, 1000)
+	_ = rl.Allow()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rl.Wait(ctx) }()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after cancellation")
+	}
+
+	if n := queuedWaiters(rl); n != 0 {
+		t.Errorf("expected empty wait queue, got %d waiters", n)
+	}
+}
+
+// queuedWaiters returns the number of goroutines queued in rl.Wait.
+func queuedWaiters(rl *RateLimiter) int {
+	rl.queue.mu.Lock()
+	defer rl.queue.mu.Unlock()
+	return len(rl.queue.waiters)
+}
+
+// TestRateLimiterDayResetUTC tests that the day window ends at UTC midnight.
+func TestRateLimiterDayResetUTC(t *testing.T) {
+	stats := NewRateLimiter().Stats()
+
+	reset := stats.DayResetAt
+	if reset.Location() != time.UTC || reset.Hour() != 0 || reset.Minute() != 0 || reset.Second() != 0 {
+		t.Errorf("expected reset at UTC midnight, got %v", reset)
+	}
+	if until := time.Until(reset); until <= 0 || until > 24*time.Hour {
+		t.Errorf("expected reset within the next day, got %v", reset)
+	}
+}
+
+// TestRateLimiterSnapshotRestore tests carrying counters over to a new limiter.
+func TestRateLimiterSnapshotRestore(t *testing.T) {
+	rl := NewRateLimiter()
+	for i := 0; i < 5; i++ {
+		_ = rl.Allow()
+	}
+
+	data, err := json.Marshal(rl.Snapshot())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var state RateLimiterState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	restored := NewRateLimiter()
+	restored.Restore(state)
+
+	stats := restored.Stats()
+	if stats.DayUsed != 5 || stats.MinuteUsed != 5 {
+		t.Errorf("expected restored usage of 5, got %+v", stats)
+	}
+	if !stats.DayResetAt.Equal(rl.Stats().DayResetAt) {
+		t.Errorf("expected restored day reset time, got %v", stats.DayResetAt)
+	}
+}
+
+// TestRateLimiterRestoreExpired tests that ended windows are not restored.
+func TestRateLimiterRestoreExpired(t *testing.T) {
+	rl := NewRateLimiter()
+	rl.Restore(RateLimiterState{
+		MinuteCount:   100,
+		MinuteResetAt: time.Now().Add(-time.Second),
+		DayCount:      900,
+		DayResetAt:    time.Now().Add(-time.Hour),
+	})
+
+	if stats := rl.Stats(); stats.DayUsed != 0 || stats.MinuteUsed != 0 {
+		t.Errorf("expected expired state to be ignored, got %+v", stats)
+	}
+}
+
+// TestRateLimiterPersistTo tests saving and loading state from a file.
+func TestRateLimiterPersistTo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.json")
+
+	rl := NewRateLimiter()
+	if err := rl.PersistTo(path); err != nil {
+		t.Fatalf("PersistTo on missing file: %v", err)
+	}
+	_ = rl.Allow()
+	_ = rl.Allow()
+	rl.Flush()
+
+	// A new limiter picks up where the old one stopped
+	restarted := NewRateLimiter()
+	if err := restarted.PersistTo(path); err != nil {
+		t.Fatalf("PersistTo: %v", err)
+	}
+	if stats := restarted.Stats(); stats.DayUsed != 2 {
+		t.Errorf("expected 2 requests carried over, got %+v", stats)
+	}
+
+	_ = restarted.Allow()
+	restarted.Flush()
+	var state RateLimiterState
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &state); err != nil || state.DayCount != 3 {
+		t.Errorf("expected saved day count of 3, got %+v (%v)", state, err)
+	}
+
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewRateLimiter().PersistTo(path); err == nil {
+		t.Error("expected error for corrupt state file")
+	}
+}
+
+// TestRateLimiterPersistConcurrent tests that coalesced background writes
+// end with the latest state.
+func TestRateLimiterPersistConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.json")
+	rl := NewRateLimiterWithLimits(1000, 1000)
+	if err := rl.PersistTo(path); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = rl.Allow()
+		}()
+	}
+	wg.Wait()
+	rl.Flush()
+
+	var state RateLimiterState
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &state); err != nil || state.DayCount != 100 {
+		t.Errorf("expected saved day count of 100, got %+v (%v)", state, err)
+	}
+}
+
+// TestParseRateLimitHeaders tests reading Digi-Key's rate limit headers.
+func TestParseRateLimitHeaders(t *testing.T) {
+	h := http.Header{}
+	if _, ok := parseRateLimitHeaders(h); ok {
+		t.Error("expected no rate limit info without headers")
+	}
+
+	h.Set("X-RateLimit-Limit", "1000")
+	h.Set("X-RateLimit-Remaining", "750")
+	h.Set("X-BurstLimit-Limit", "120")
+	h.Set("X-BurstLimit-Remaining", "bogus")
+	h.Set("X-BurstLimit-Reset", "12")
+
+	limit, ok := parseRateLimitHeaders(h)
+	if !ok {
+		t.Fatal("expected rate limit info")
+	}
+	want := ServerRateLimit{
+		DayLimit:        1000,
+		DayRemaining:    750,
+		MinuteLimit:     120,
+		MinuteRemaining: -1,
+		MinuteReset:     12 * time.Second,
+	}
+	if limit != want {
+		t.Errorf("got %+v, want %+v", limit, want)
+	}
+}
+
+// TestRateLimiterSyncFromServer tests adopting the server's counts.
+func TestRateLimiterSyncFromServer(t *testing.T) {
+	rl := NewRateLimiter()
+	for i := 0; i < 10; i++ {
+		_ = rl.Allow()
+	}
+
+	rl.SyncFromServer(ServerRateLimit{
+		DayLimit:        2000,
+		DayRemaining:    1500,
+		MinuteLimit:     120,
+		MinuteRemaining: 115,
+		MinuteReset:     20 * time.Second,
+	})
+
+	stats := rl.Stats()
+	if stats.DayLimit != 2000 || stats.DayUsed != 500 {
+		t.Errorf("expected server day usage 500/2000, got %d/%d", stats.DayUsed, stats.DayLimit)
+	}
+	if stats.MinuteUsed != 5 {
+		t.Errorf("expected server minute usage 5, got %d", stats.MinuteUsed)
+	}
+	if until := time.Until(stats.MinuteResetAt); until > 20*time.Second || until < 19*time.Second {
+		t.Errorf("expected minute reset in ~20s, got %v", until)
+	}
+
+	// Absent headers leave the state alone
+	rl.SyncFromServer(ServerRateLimit{DayLimit: -1, DayRemaining: -1, MinuteLimit: -1, MinuteRemaining: -1})
+	if after := rl.Stats(); after.DayUsed != 500 || after.MinuteUsed != 5 {
+		t.Errorf("expected unchanged stats, got %+v", after)
+	}
+}
+
+// TestRateLimiterSyncFromServerReset tests that a new server window lifts
+// local throttling.
+func TestRateLimiterSyncFromServerReset(t *testing.T) {
+	rl := NewRateLimiterWithLimits(2, 1000)
+	_ = rl.Allow()
+	_ = rl.Allow()
+	if err := rl.Allow(); err == nil {
+		t.Fatal("expected the local minute window to be exhausted")
+	}
+
+	rl.SyncFromServer(ServerRateLimit{
+		DayLimit:        -1,
+		DayRemaining:    -1,
+		MinuteLimit:     -1,
+		MinuteRemaining: 2,
+		MinuteReset:     30 * time.Second,
+	})
+	if err := rl.Allow(); err != nil {
+		t.Errorf("expected the server's new window to allow requests, got %v", err)
+	}
+	if until := time.Until(rl.Stats().MinuteResetAt); until > 30*time.Second || until < 29*time.Second {
+		t.Errorf("expected minute reset in ~30s, got %v", until)
+	}
+}
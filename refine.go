@@ -0,0 +1,165 @@
+package digikey
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxResolveCandidates caps the candidates listed in a ResolveError.
+const maxResolveCandidates = 10
+
+// Refinement narrows a previous search using the filter options returned with
+// its response, so filters can be chosen by name instead of Digi-Key ID.
+//
+//	next, err := digikey.NewRefinement(req, resp).
+//	    Refine("Voltage - Supply", "3.3V").
+//	    RefineManufacturer("Texas Instruments").
+//	    Build()
+type Refinement struct {
+	options FilterOptions
+	search  *SearchOptions
+}
+
+// NewRefinement starts a refinement of req using the filter options in resp.
+// The refined request starts again at offset 0; req is not modified.
+func NewRefinement(req *SearchRequest, resp *SearchResponse) *Refinement {
+	r := &Refinement{search: &SearchOptions{}}
+	if req == nil || resp == nil {
+		r.search.errs = append(r.search.errs, fmt.Errorf("%w: refinement requires a request and its response", ErrInvalidRequest))
+		return r
+	}
+
+	r.options = resp.FilterOptions
+	r.search.request = *req
+	r.search.request.Offset = 0
+	return r
+}
+
+// Refine restricts results to products whose parameter has one of values.
+// Parameter and value names are matched without regard to case or spacing.
+func (r *Refinement) Refine(parameter string, values ...string) *Refinement {
+	if len(values) == 0 {
+		r.search.errs = append(r.search.errs, fmt.Errorf("%w: parameter %q requires at least one value", ErrInvalidRequest, parameter))
+		return r
+	}
+
+	var param *ParametricFilterOption
+	for i := range r.options.ParametricFilters {
+		if sameFilterName(r.options.ParametricFilters[i].ParameterName, parameter) {
+			param = &r.options.ParametricFilters[i]
+			break
+		}
+	}
+	if param == nil {
+		names := make([]string, len(r.options.ParametricFilters))
+		for i, p := range r.options.ParametricFilters {
+			names[i] = p.ParameterName
+		}
+		r.search.errs = append(r.search.errs, newResolveError("parameter", parameter, names))
+		return r
+	}
+
+	valueIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		var found bool
+		for _, v := range param.Values {
+			if sameFilterName(v.ValueText, value) {
+				valueIDs = append(valueIDs, v.ValueID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			texts := make([]string, len(param.Values))
+			for i, v := range param.Values {
+				texts[i] = v.ValueText
+			}
+			r.search.errs = append(r.search.errs, newResolveError(param.ParameterName+" value", value, texts))
+			return r
+		}
+	}
+
+	r.search.FilterByParameter(param.ParameterID, valueIDs...)
+	return r
+}
+
+// RefineCategory restricts results to the named category.
+func (r *Refinement) RefineCategory(name string) *Refinement {
+	names := make([]string, len(r.options.Categories))
+	for i, c := range r.options.Categories {
+		if sameFilterName(c.Category.Name, name) {
+			r.search.FilterByCategory(c.Category.CategoryID)
+			return r
+		}
+		names[i] = c.Category.Name
+	}
+	r.search.errs = append(r.search.errs, newResolveError("category", name, names))
+	return r
+}
+
+// RefineManufacturer restricts results to the named manufacturer.
+func (r *Refinement) RefineManufacturer(name string) *Refinement {
+	names := make([]string, len(r.options.Manufacturers))
+	for i, m := range r.options.Manufacturers {
+		if sameFilterName(m.Manufacturer.Name, name) {
+			r.search.FilterByManufacturer(m.Manufacturer.ID)
+			return r
+		}
+		names[i] = m.Manufacturer.Name
+	}
+	r.search.errs = append(r.search.errs, newResolveError("manufacturer", name, names))
+	return r
+}
+
+// RefineStatus restricts results to the named product status, such as "Active".
+func (r *Refinement) RefineStatus(name string) *Refinement {
+	names := make([]string, len(r.options.Status))
+	for i, s := range r.options.Status {
+		if sameFilterName(s.StatusName, name) {
+			r.search.FilterByStatus(s.StatusID)
+			return r
+		}
+		names[i] = s.StatusName
+	}
+	r.search.errs = append(r.search.errs, newResolveError("status", name, names))
+	return r
+}
+
+// RefinePackageType restricts results to the named package type, such as "Cut Tape (CT)".
+func (r *Refinement) RefinePackageType(name string) *Refinement {
+	names := make([]string, len(r.options.PackageTypes))
+	for i, p := range r.options.PackageTypes {
+		if sameFilterName(p.PackageType.Name, name) {
+			r.search.FilterByPackageType(p.PackageType.ID)
+			return r
+		}
+		names[i] = p.PackageType.Name
+	}
+	r.search.errs = append(r.search.errs, newResolveError("package type", name, names))
+	return r
+}
+
+// Build returns the refined SearchRequest, or the errors for any names or
+// values that were not offered by the previous response.
+func (r *Refinement) Build() (*SearchRequest, error) {
+	return r.search.Build()
+}
+
+// Execute performs the refined search using the provided client.
+func (r *Refinement) Execute(ctx context.Context, client *Client) (*SearchResponse, error) {
+	return r.search.Execute(ctx, client)
+}
+
+// sameFilterName reports whether two filter names match, ignoring case and whitespace.
+func sameFilterName(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), ""), strings.Join(strings.Fields(b), ""))
+}
+
+// newResolveError creates a ResolveError listing the offered names as candidates.
+func newResolveError(kind, name string, offered []string) *ResolveError {
+	if len(offered) > maxResolveCandidates {
+		offered = offered[:maxResolveCandidates]
+	}
+	return &ResolveError{Kind: kind, Name: name, Candidates: offered}
+}
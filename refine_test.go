@@ -0,0 +1,165 @@
+package digikey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func testFilterResponse() *SearchResponse {
+	return &SearchResponse{
+		FilterOptions: FilterOptions{
+			Categories: []CategoryFilter{
+				{Category: Category{CategoryID: 685, Name: "Microcontrollers"}},
+			},
+			Manufacturers: []ManufacturerFilter{
+				{Manufacturer: Manufacturer{ID: 296, Name: "Texas Instruments"}},
+				{Manufacturer: Manufacturer{ID: 497, Name: "STMicroelectronics"}},
+			},
+			Status: []StatusFilter{
+				{StatusID: 0, StatusName: "Active"},
+				{StatusID: 7, StatusName: "Obsolete"},
+			},
+			PackageTypes: []PackageTypeFilter{
+				{PackageType: PackageType{ID: 2, Name: "Cut Tape (CT)"}},
+			},
+			ParametricFilters: []ParametricFilterOption{
+				{
+					ParameterID:   2079,
+					ParameterName: "Voltage - Supply",
+					Values: []FilterValue{
+						{ValueID: "330", ValueText: "3.3V"},
+						{ValueID: "500", ValueText: "5V"},
+					},
+				},
+				{
+					ParameterID:   16,
+					ParameterName: "Package / Case",
+					Values: []FilterValue{
+						{ValueID: "39", ValueText: "8-SOIC (0.154\", 3.90mm Width)"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestRefinementBuild tests refining a request by filter names.
+func TestRefinementBuild(t *testing.T) {
+	prev := &SearchRequest{Keywords: "op amp", Limit: 25, Offset: 50}
+
+	req, err := NewRefinement(prev, testFilterResponse()).
+		Refine("voltage - supply", "3.3 V", "5V").
+		RefineCategory("Microcontrollers").
+		RefineManufacturer("texas instruments").
+		RefinePackageType("Cut Tape (CT)").
+		Build()
+	if err != nil {
+		t.Fatalf("Build() returned error: %v", err)
+	}
+
+	if req.Keywords != "op amp" || req.Limit != 25 {
+		t.Errorf("expected keywords and limit to be kept, got %+v", req)
+	}
+	if req.Offset != 0 {
+		t.Errorf("expected offset to reset to 0, got %d", req.Offset)
+	}
+	if prev.FilterOptionsRequest != nil || prev.Offset != 50 {
+		t.Error("previous request should not be modified")
+	}
+
+	f := req.FilterOptionsRequest
+	if len(f.ParameterFilterRequest) != 1 {
+		t.Fatalf("expected 1 parameter filter, got %d", len(f.ParameterFilterRequest))
+	}
+	pf := f.ParameterFilterRequest[0]
+	if pf.ParameterID != 2079 || len(pf.ValueIDs) != 2 || pf.ValueIDs[0] != "330" || pf.ValueIDs[1] != "500" {
+		t.Errorf("unexpected parameter filter %+v", pf)
+	}
+	if len(f.CategoryFilter) != 1 || f.CategoryFilter[0] != 685 {
+		t.Errorf("unexpected category filter %v", f.CategoryFilter)
+	}
+	if len(f.ManufacturerFilter) != 1 || f.ManufacturerFilter[0] != 296 {
+		t.Errorf("unexpected manufacturer filter %v", f.ManufacturerFilter)
+	}
+	if len(f.PackageTypeFilter) != 1 || f.PackageTypeFilter[0] != 2 {
+		t.Errorf("unexpected package type filter %v", f.PackageTypeFilter)
+	}
+}
+
+// TestRefinementUnknownNames tests errors for names not offered by the response.
+func TestRefinementUnknownNames(t *testing.T) {
+	req := &SearchRequest{Keywords: "op amp"}
+
+	_, err := NewRefinement(req, testFilterResponse()).Refine("Voltage - Output", "3.3V").Build()
+	var resolveErr *ResolveError
+	if !errors.As(err, &resolveErr) {
+		t.Fatalf("expected ResolveError, got %v", err)
+	}
+	if resolveErr.Kind != "parameter" || len(resolveErr.Candidates) != 2 {
+		t.Errorf("expected parameter error listing 2 offered parameters, got %+v", resolveErr)
+	}
+
+	_, err = NewRefinement(req, testFilterResponse()).Refine("Voltage - Supply", "12V").Build()
+	if !errors.As(err, &resolveErr) {
+		t.Fatalf("expected ResolveError, got %v", err)
+	}
+	if resolveErr.Kind != "Voltage - Supply value" || resolveErr.Name != "12V" {
+		t.Errorf("unexpected error %+v", resolveErr)
+	}
+	if len(resolveErr.Candidates) != 2 || resolveErr.Candidates[0] != "3.3V" {
+		t.Errorf("expected offered values as candidates, got %v", resolveErr.Candidates)
+	}
+
+	_, err = NewRefinement(req, testFilterResponse()).
+		RefineManufacturer("Acme").
+		RefineStatus("Preliminary").
+		Build()
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
+	if !contains(err.Error(), `"Acme"`) || !contains(err.Error(), `"Preliminary"`) {
+		t.Errorf("expected both unknown names to be reported, got %v", err)
+	}
+
+	if _, err := NewRefinement(nil, nil).Build(); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for nil inputs, got %v", err)
+	}
+}
+
+// TestRefinementStatusZeroID tests that the Active status (ID 0) is accepted.
+func TestRefinementStatusZeroID(t *testing.T) {
+	_, err := NewRefinement(&SearchRequest{Keywords: "op amp"}, testFilterResponse()).RefineStatus("Active").Build()
+	if err != nil {
+		t.Fatalf("Build() returned error: %v", err)
+	}
+}
+
+// TestRefinementExecute tests executing a refined search.
+func TestRefinementExecute(t *testing.T) {
+	var searched SearchRequest
+	client := newMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&searched)
+		_, _ = w.Write([]byte(`{"Products":[],"ProductsCount":0}`))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := NewRefinement(&SearchRequest{Keywords: "op amp"}, testFilterResponse()).
+		Refine("Package / Case", "8-SOIC (0.154\", 3.90mm Width)").
+		Execute(ctx, client)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if searched.FilterOptionsRequest == nil || len(searched.FilterOptionsRequest.ParameterFilterRequest) != 1 {
+		t.Fatalf("expected parameter filter in request, got %+v", searched.FilterOptionsRequest)
+	}
+	if searched.FilterOptionsRequest.ParameterFilterRequest[0].ValueIDs[0] != "39" {
+		t.Errorf("unexpected value IDs %v", searched.FilterOptionsRequest.ParameterFilterRequest[0].ValueIDs)
+	}
+}
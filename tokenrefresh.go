@@ -0,0 +1,154 @@
+package digikey
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenRefreshConfig configures background token refresh.
+type TokenRefreshConfig struct {
+	// Lead is how long before the access token expires to renew it. Tokens
+	// that live shorter than Lead are renewed halfway through their
+	// lifetime. Defaults to 5 minutes.
+	Lead time.Duration
+
+	// Backoff sets the delay between failed attempts. MaxRetries is ignored;
+	// the refresher keeps trying until it succeeds or the client is closed.
+	// Defaults to DefaultRetryConfig.
+	Backoff RetryConfig
+}
+
+// DefaultTokenRefreshConfig returns the default background refresh configuration.
+func DefaultTokenRefreshConfig() TokenRefreshConfig {
+	return TokenRefreshConfig{
+		Lead:    5 * time.Minute,
+		Backoff: DefaultRetryConfig(),
+	}
+}
+
+// TokenHealth reports the state of the client's access token, for example
+// for readiness probes.
+type TokenHealth struct {
+	Valid               bool      // An unexpired access token is held
+	Expiry              time.Time // When the access token expires; zero if there is none
+	LastRefresh         time.Time // When a token was last obtained
+	LastError           error     // Error from the last refresh attempt; nil if it succeeded
+	LastErrorTime       time.Time // When the last failed attempt happened
+	ConsecutiveFailures int       // Failed attempts since the last success
+}
+
+// WithBackgroundTokenRefresh renews the access token in the background
+// before it expires, so requests never wait for a token round-trip. Failed
+// renewals are retried with backoff while the current token remains in use;
+// see TokenHealth. Close stops the refresher.
+func WithBackgroundTokenRefresh(config TokenRefreshConfig) ClientOption {
+	return func(c *Client) {
+		defaults := DefaultTokenRefreshConfig()
+		if config.Lead <= 0 {
+			config.Lead = defaults.Lead
+		}
+		if config.Backoff.InitialBackoff <= 0 {
+			config.Backoff = defaults.Backoff
+		}
+		c.tokenRefresh = &config
+	}
+}
+
+// TokenHealth returns the state of the client's access token and of the
+// most recent refresh attempt.
+func (c *Client) TokenHealth() TokenHealth {
+	return c.tokenManager.health()
+}
+
+// health returns the token state.
+func (tm *tokenManager) health() TokenHealth {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	h := TokenHealth{
+		Valid:               tm.accessToken != "" && time.Now().Before(tm.tokenExpiry),
+		LastRefresh:         tm.lastRefresh,
+		LastError:           tm.lastError,
+		LastErrorTime:       tm.lastErrorTime,
+		ConsecutiveFailures: tm.failures,
+	}
+	if tm.accessToken != "" {
+		h.Expiry = tm.tokenExpiry
+	}
+	return h
+}
+
+// recordRefresh records the outcome of a refresh attempt.
+func (tm *tokenManager) recordRefresh(err error) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if err != nil {
+		tm.lastError = err
+		tm.lastErrorTime = time.Now()
+		tm.failures++
+		return
+	}
+	tm.lastRefresh = time.Now()
+	tm.lastError = nil
+	tm.failures = 0
+}
+
+// refreshDelay returns how long to wait before renewing the access token.
+func (tm *tokenManager) refreshDelay(lead time.Duration) time.Duration {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	if tm.accessToken == "" {
+		return 0
+	}
+	remaining := time.Until(tm.tokenExpiry)
+	if remaining-lead > remaining/2 {
+		return remaining - lead
+	}
+	if remaining > 0 {
+		return remaining / 2
+	}
+	return 0
+}
+
+// startRefresher renews the access token in the background until the
+// returned function is called, which waits for the refresher to exit.
+func (tm *tokenManager) startRefresher(config TokenRefreshConfig) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		tm.refreshLoop(ctx, config)
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// refreshLoop renews the access token config.Lead before it expires,
+// backing off after failures, until ctx ends.
+func (tm *tokenManager) refreshLoop(ctx context.Context, config TokenRefreshConfig) {
+	failures := 0
+	for {
+		wait := tm.refreshDelay(config.Lead)
+		if failures > 0 {
+			wait = config.Backoff.calculateBackoff(failures - 1)
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return
+		}
+
+		if _, err := tm.renew(ctx, config.Lead); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			failures++
+			continue
+		}
+		failures = 0
+	}
+}
@@ -0,0 +1,125 @@
+package digikey
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestBackgroundTokenRefresh tests renewing a token before it expires.
+func TestBackgroundTokenRefresh(t *testing.T) {
+	var calls atomic.Int32
+	server := countingTokenServer(t, &calls)
+
+	tm := newTokenManager(server.Client(), "id", "secret", server.URL)
+	tm.accessToken = "current"
+	tm.tokenExpiry = time.Now().Add(200 * time.Millisecond)
+
+	// The token expires within the lead time, so it is renewed halfway
+	// through its remaining lifetime.
+	stop := tm.startRefresher(TokenRefreshConfig{Lead: time.Hour, Backoff: DefaultRetryConfig()})
+	defer stop()
+
+	waitFor(t, func() bool { return !tm.health().LastRefresh.IsZero() })
+	health := tm.health()
+	if !health.Valid || health.LastError != nil || calls.Load() != 1 {
+		t.Errorf("unexpected health %+v", health)
+	}
+	if token := tm.token(); token.AccessToken != "shared-token" {
+		t.Errorf("expected the renewed token, got %q", token.AccessToken)
+	}
+}
+
+// TestBackgroundTokenRefreshBackoff tests retrying failed refreshes and
+// reporting them in TokenHealth.
+func TestBackgroundTokenRefreshBackoff(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("id", "secret",
+		WithTokenURL(server.URL),
+		WithoutRetry(),
+		WithBackgroundTokenRefresh(TokenRefreshConfig{
+			Backoff: RetryConfig{InitialBackoff: 50 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2},
+		}),
+	)
+	defer func() { _ = client.Close() }()
+
+	waitFor(t, func() bool { return calls.Load() >= 1 })
+	waitFor(t, func() bool { return client.TokenHealth().LastError != nil })
+	if health := client.TokenHealth(); health.Valid || health.ConsecutiveFailures == 0 || health.LastErrorTime.IsZero() {
+		t.Errorf("expected a failed refresh, got %+v", health)
+	}
+
+	waitFor(t, func() bool { return client.TokenHealth().Valid })
+	health := client.TokenHealth()
+	if health.LastError != nil || health.ConsecutiveFailures != 0 {
+		t.Errorf("expected the error to clear after a successful refresh, got %+v", health)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 token requests, got %d", got)
+	}
+}
+
+// TestTokenRefreshDoesNotBlockReaders tests that a still-valid token is
+// returned while a renewal is in flight.
+func TestTokenRefreshDoesNotBlockReaders(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"access_token":"new","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	tm := newTokenManager(server.Client(), "id", "secret", server.URL)
+	tm.accessToken = "current"
+	tm.tokenExpiry = time.Now().Add(10 * time.Minute)
+
+	go func() { _, _ = tm.renew(context.Background(), time.Hour) }()
+	waitFor(t, func() bool {
+		if !tm.refreshMu.TryLock() {
+			return true
+		}
+		tm.refreshMu.Unlock()
+		return false
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	token, err := tm.getToken(ctx)
+	if err != nil || token != "current" {
+		t.Errorf("expected the current token during renewal, got %q, %v", token, err)
+	}
+}
+
+// TestTokenHealthWithoutToken tests health before any token is fetched.
+func TestTokenHealthWithoutToken(t *testing.T) {
+	client := NewClient("id", "secret")
+	if health := client.TokenHealth(); health.Valid || !health.Expiry.IsZero() || health.LastError != nil {
+		t.Errorf("unexpected health %+v", health)
+	}
+}
@@ -0,0 +1,212 @@
+package digikey
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+)
+
+// TokenStore persists OAuth2 tokens so that clients can reuse them across
+// restarts and share them between processes instead of each fetching its
+// own. Keys identify the client ID and grant type. Implementations must be
+// safe for concurrent use.
+type TokenStore interface {
+	// Load returns the token for key, or the zero Token if there is none.
+	Load(ctx context.Context, key string) (Token, error)
+
+	// Save stores the token for key.
+	Save(ctx context.Context, key string, token Token) error
+
+	// Delete removes the token for key.
+	Delete(ctx context.Context, key string) error
+}
+
+// TokenLocker is implemented by token stores that can lock a key against
+// other processes. Clients hold the lock while refreshing, so only one of
+// them fetches a new token and a rotated refresh token is never lost.
+type TokenLocker interface {
+	// LockToken blocks until the lock for key is held or ctx ends, and
+	// returns a function that releases it.
+	LockToken(ctx context.Context, key string) (unlock func(), err error)
+}
+
+// MemoryTokenStore is a TokenStore for clients within one process.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+	locks  map[string]chan struct{}
+}
+
+// NewMemoryTokenStore creates an empty in-memory token store.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		tokens: make(map[string]Token),
+		locks:  make(map[string]chan struct{}),
+	}
+}
+
+// Load returns the token for key.
+func (s *MemoryTokenStore) Load(_ context.Context, key string) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[key], nil
+}
+
+// Save stores the token for key.
+func (s *MemoryTokenStore) Save(_ context.Context, key string, token Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[key] = token
+	return nil
+}
+
+// Delete removes the token for key.
+func (s *MemoryTokenStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, key)
+	return nil
+}
+
+// LockToken locks key against other clients sharing the store.
+func (s *MemoryTokenStore) LockToken(ctx context.Context, key string) (func(), error) {
+	s.mu.Lock()
+	lock, ok := s.locks[key]
+	if !ok {
+		lock = make(chan struct{}, 1)
+		s.locks[key] = lock
+	}
+	s.mu.Unlock()
+
+	select {
+	case lock <- struct{}{}:
+		return func() { <-lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// FileTokenStore is a TokenStore kept in a file, for processes on one host.
+// Tokens for all keys share the file, which is only readable by its owner.
+// Updates are serialized with a lock file created next to it, and the file
+// is replaced atomically so readers never see a partial write.
+//
+// Tokens grant access to the Digi-Key account; use
+// NewEncryptedFileTokenStore unless the file is otherwise protected.
+type FileTokenStore struct {
+	path string
+	aead cipher.AEAD // nil for plain JSON
+}
+
+// NewFileTokenStore creates a store that keeps tokens as plain JSON in the
+// file at path. The file is created when first needed.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// NewEncryptedFileTokenStore creates a store that keeps tokens in the file
+// at path, encrypted with AES-GCM. key must be 16, 24 or 32 bytes long.
+func NewEncryptedFileTokenStore(path string, key []byte) (*FileTokenStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: token store key: %v", ErrInvalidRequest, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &FileTokenStore{path: path, aead: aead}, nil
+}
+
+// Load returns the token for key.
+func (s *FileTokenStore) Load(_ context.Context, key string) (Token, error) {
+	tokens, err := s.read()
+	if err != nil {
+		return Token{}, err
+	}
+	return tokens[key], nil
+}
+
+// Save stores the token for key.
+func (s *FileTokenStore) Save(ctx context.Context, key string, token Token) error {
+	return s.update(ctx, func(tokens map[string]Token) {
+		tokens[key] = token
+	})
+}
+
+// Delete removes the token for key.
+func (s *FileTokenStore) Delete(ctx context.Context, key string) error {
+	return s.update(ctx, func(tokens map[string]Token) {
+		delete(tokens, key)
+	})
+}
+
+// LockToken locks the store against other processes. The lock covers all
+// keys in the file.
+func (s *FileTokenStore) LockToken(ctx context.Context, _ string) (func(), error) {
+	return lockFile(ctx, s.path+".refresh.lock")
+}
+
+// update applies fn to the stored tokens while holding the write lock.
+func (s *FileTokenStore) update(ctx context.Context, fn func(tokens map[string]Token)) error {
+	unlock, err := lockFile(ctx, s.path+".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	fn(tokens)
+
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	if s.aead != nil {
+		nonce := make([]byte, s.aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return err
+		}
+		data = s.aead.Seal(nonce, nonce, data, nil)
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// read returns the stored tokens, or an empty map if the file is missing.
+func (s *FileTokenStore) read() (map[string]Token, error) {
+	tokens := make(map[string]Token)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return tokens, nil
+	}
+
+	if s.aead != nil {
+		size := s.aead.NonceSize()
+		if len(data) < size {
+			return nil, fmt.Errorf("reading %s: truncated", s.path)
+		}
+		if data, err = s.aead.Open(nil, data[:size], data[size:], nil); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", s.path, err)
+		}
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", s.path, err)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,258 @@
+package digikey
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestTokenStoreInterface verifies the stores implement TokenStore and TokenLocker.
+func TestTokenStoreInterface(t *testing.T) {
+	var _ TokenStore = (*MemoryTokenStore)(nil)
+	var _ TokenLocker = (*MemoryTokenStore)(nil)
+	var _ TokenStore = (*FileTokenStore)(nil)
+	var _ TokenLocker = (*FileTokenStore)(nil)
+}
+
+// testTokenStore exercises Load, Save and Delete on store.
+func testTokenStore(t *testing.T, store TokenStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	if token, err := store.Load(ctx, "id:client_credentials"); err != nil || token != (Token{}) {
+		t.Fatalf("expected empty token, got %+v, %v", token, err)
+	}
+
+	saved := Token{AccessToken: "access", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour).UTC().Truncate(time.Second)}
+	if err := store.Save(ctx, "id:client_credentials", saved); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := store.Save(ctx, "other:client_credentials", Token{AccessToken: "other"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	token, err := store.Load(ctx, "id:client_credentials")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if token.AccessToken != saved.AccessToken || token.RefreshToken != saved.RefreshToken || !token.Expiry.Equal(saved.Expiry) {
+		t.Errorf("loaded %+v, want %+v", token, saved)
+	}
+
+	if err := store.Delete(ctx, "id:client_credentials"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if token, _ := store.Load(ctx, "id:client_credentials"); token != (Token{}) {
+		t.Errorf("expected deleted token, got %+v", token)
+	}
+	if token, _ := store.Load(ctx, "other:client_credentials"); token.AccessToken != "other" {
+		t.Errorf("other key should be kept, got %+v", token)
+	}
+}
+
+// TestMemoryTokenStore tests the in-memory store.
+func TestMemoryTokenStore(t *testing.T) {
+	testTokenStore(t, NewMemoryTokenStore())
+}
+
+// TestFileTokenStore tests the plain file store.
+func TestFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	testTokenStore(t, NewFileTokenStore(path))
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected mode 0600, got %o", perm)
+	}
+}
+
+// TestFileTokenStoreReadError tests that read errors other than a missing
+// file are returned instead of treated as an empty store.
+func TestFileTokenStoreReadError(t *testing.T) {
+	store := NewFileTokenStore(t.TempDir()) // A directory cannot be read as a file
+
+	if _, err := store.Load(context.Background(), "key"); err == nil {
+		t.Error("expected an error loading from an unreadable file")
+	}
+	if err := store.Save(context.Background(), "key", Token{AccessToken: "x"}); err == nil {
+		t.Error("expected an error saving over an unreadable file")
+	}
+}
+
+// TestEncryptedFileTokenStore tests the encrypted file store.
+func TestEncryptedFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.enc")
+	key := bytes.Repeat([]byte{7}, 32)
+	store, err := NewEncryptedFileTokenStore(path, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testTokenStore(t, store)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("other")) {
+		t.Error("token file should be encrypted")
+	}
+
+	wrongKey, _ := NewEncryptedFileTokenStore(path, bytes.Repeat([]byte{8}, 32))
+	if _, err := wrongKey.Load(context.Background(), "other:client_credentials"); err == nil {
+		t.Error("expected an error loading with the wrong key")
+	}
+
+	if _, err := NewEncryptedFileTokenStore(path, []byte("short")); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for a bad key, got %v", err)
+	}
+}
+
+// countingTokenServer returns a token endpoint that counts requests.
+func countingTokenServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"access_token":"shared-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestTokenStoreSharedBetweenClients tests that a new client reuses a stored token.
+func TestTokenStoreSharedBetweenClients(t *testing.T) {
+	var calls atomic.Int32
+	server := countingTokenServer(t, &calls)
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		client := NewClient("id", "secret", WithTokenURL(server.URL), WithTokenStore(NewFileTokenStore(path)))
+		token, err := client.tokenManager.getToken(ctx)
+		if err != nil {
+			t.Fatalf("getToken: %v", err)
+		}
+		if token != "shared-token" {
+			t.Errorf("unexpected token %q", token)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 token request, got %d", calls.Load())
+	}
+}
+
+// TestTokenStoreConcurrentRefresh tests that clients refreshing at once
+// through separate store instances make one token request.
+func TestTokenStoreConcurrentRefresh(t *testing.T) {
+	var calls atomic.Int32
+	server := countingTokenServer(t, &calls)
+	path := filepath.Join(t.TempDir(), "tokens.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tm := newTokenManager(server.Client(), "id", "secret", server.URL)
+			tm.store = NewFileTokenStore(path)
+			if _, err := tm.getToken(context.Background()); err != nil {
+				t.Errorf("getToken: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 token request, got %d", calls.Load())
+	}
+}
+
+// TestTokenStoreInvalidate tests that a rejected token is cleared from the store.
+func TestTokenStoreInvalidate(t *testing.T) {
+	var calls atomic.Int32
+	server := countingTokenServer(t, &calls)
+	store := NewMemoryTokenStore()
+	ctx := context.Background()
+
+	tm := newTokenManager(server.Client(), "id", "secret", server.URL)
+	tm.store = store
+	if _, err := tm.getToken(ctx); err != nil {
+		t.Fatalf("getToken: %v", err)
+	}
+
+	tm.invalidate(ctx)
+	if token, _ := store.Load(ctx, "id:client_credentials"); token.AccessToken != "" {
+		t.Errorf("expected stored access token to be cleared, got %+v", token)
+	}
+
+	if _, err := tm.getToken(ctx); err != nil {
+		t.Fatalf("getToken: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("expected a new token request after invalidate, got %d", calls.Load())
+	}
+}
+
+// TestTokenStoreInvalidateLocked tests that invalidating waits for the
+// refresh lock, so it cannot clear a token another client is storing.
+func TestTokenStoreInvalidateLocked(t *testing.T) {
+	var calls atomic.Int32
+	server := countingTokenServer(t, &calls)
+	store := NewMemoryTokenStore()
+
+	tm := newTokenManager(server.Client(), "id", "secret", server.URL)
+	tm.store = store
+	if _, err := tm.getToken(context.Background()); err != nil {
+		t.Fatalf("getToken: %v", err)
+	}
+
+	unlock, err := store.LockToken(context.Background(), "id:client_credentials")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	tm.invalidate(ctx)
+
+	if _, ok := tm.validToken(0); ok {
+		t.Error("expected the cached access token to be cleared")
+	}
+	if token, _ := store.Load(context.Background(), "id:client_credentials"); token.AccessToken == "" {
+		t.Error("expected the stored token to be kept while another client holds the lock")
+	}
+}
+
+// TestTokenStoreAuthorizationCode tests resuming a login from the store.
+func TestTokenStoreAuthorizationCode(t *testing.T) {
+	server := newOAuthServer(t)
+	store := NewMemoryTokenStore()
+	ctx := context.Background()
+
+	first := server.client(WithTokenStore(store))
+	if _, err := first.ExchangeCode(ctx, "good-code"); err != nil {
+		t.Fatalf("ExchangeCode: %v", err)
+	}
+
+	second := server.client(WithTokenStore(store))
+	resp, err := second.ProductDetails(ctx, "P5555-ND")
+	if err != nil {
+		t.Fatalf("ProductDetails: %v", err)
+	}
+	if resp.Product.DigiKeyProductNumber != "Bearer access-1" {
+		t.Errorf("expected the stored access token, got %q", resp.Product.DigiKeyProductNumber)
+	}
+}